@@ -45,12 +45,21 @@ var containerCmd = &cobra.Command{
 			return err
 		}
 
+		output, err := containerCmd.sinkOpts.openOutput()
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
 		// create a metric scraper factory that will help construct
 		// a container metric specific MetricScraper.
 		metricScraperFactory := factory.
 			NewMetricScraperFactory().
 			ForCommand(core.ContainerCommand).
-			WithScrapeInterval(containerCmd.refreshRate)
+			WithScrapeInterval(containerCmd.refreshRate).
+			WithSinks(containerCmd.sinkOpts.sinks...).
+			WithOutput(output).
+			WithAddress(containerCmd.sinkOpts.metricsAddress)
 
 		if containerCmd.isPerContainer() {
 			metricScraperFactory = metricScraperFactory.ForSingularEntity(containerCmd.cid)
@@ -62,11 +71,14 @@ var containerCmd = &cobra.Command{
 			return err
 		}
 
+		opts := []factory.Option{}
 		if containerCmd.all {
-			err = containerMetricScraper.Serve(factory.WithAllAs(containerCmd.all))
-		} else {
-			err = containerMetricScraper.Serve()
+			opts = append(opts, factory.WithAllAs(containerCmd.all))
+		}
+		if !containerCmd.isPerContainer() {
+			opts = append(opts, factory.WithReadOnlyAs(containerCmd.readOnly))
 		}
+		err = containerMetricScraper.Serve(opts...)
 
 		if err != nil && err != core.ErrCanceledByUser {
 			if err == core.ErrInvalidContainer {
@@ -80,9 +92,11 @@ var containerCmd = &cobra.Command{
 }
 
 type containerCommand struct {
+	sinkOpts    *sinkOptions
 	refreshRate uint64
 	cid         string
 	all         bool
+	readOnly    bool
 }
 
 func constructContainerCommand(cmd *cobra.Command, args []string) (*containerCommand, error) {
@@ -104,14 +118,26 @@ func constructContainerCommand(cmd *cobra.Command, args []string) (*containerCom
 		return nil, errors.New("error extracting flag --refresh")
 	}
 
-	if containerRefreshRate < 1000 {
-		return nil, errors.New("invalid refresh rate: minimum refresh rate is 1000(ms)")
+	if containerRefreshRate < minRefreshRate {
+		return nil, fmt.Errorf("invalid refresh rate: minimum refresh rate is %d(ms)", minRefreshRate)
+	}
+
+	readOnly, err := readOnlyFrom(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	sinkOpts, err := constructSinkOptions(cmd)
+	if err != nil {
+		return nil, err
 	}
 
 	containerCmd := &containerCommand{
 		refreshRate: containerRefreshRate,
 		cid:         cid,
 		all:         allFlag,
+		readOnly:    readOnly,
+		sinkOpts:    sinkOpts,
 	}
 
 	return containerCmd, nil
@@ -135,7 +161,7 @@ func init() {
 		"refresh",
 		"r",
 		defaultContainerRefreshRate,
-		"Container information UI refreshes rate in milliseconds greater than 1000",
+		"Container information UI refreshes rate in milliseconds greater than 200",
 	)
 
 	containerCmd.Flags().BoolP(
@@ -144,4 +170,7 @@ func init() {
 		false,
 		"Specify to list all containers or only running containers.",
 	)
+
+	addReadOnlyFlag(containerCmd)
+	addSinkFlags(containerCmd)
 }
@@ -20,16 +20,24 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/factory"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	processGraph "github.com/pesos/grofer/pkg/sink/tui/process"
 	"github.com/pesos/grofer/pkg/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const (
 	defaultProcRefreshRate = 3000
 	defaultProcPid         = ""
+	defaultProcFilter      = ""
+	defaultProcUser        = ""
+	defaultProcName        = ""
+	defaultProcMatch       = ""
 )
 
 // procCmd represents the proc command
@@ -44,7 +52,51 @@ Syntax:
 To get information about a particular process whose PID is known the -p or --pid flag can be used.
 
 Syntax:
-  grofer proc -p [PID]`,
+  grofer proc -p [PID]
+
+To compare up to six processes side by side and on shared graphs, with one
+colour for every process, a comma separated list of PIDs can be passed.
+
+Syntax:
+  grofer proc -p [PID],[PID],[PID]
+
+To attach to the most recently started process whose command name or
+executable is known, or whose executable or command line matches a regular
+expression, the --name or --match flag can be used. With the --follow flag
+the newest matching process is attached to once the current one exits, and
+the restart is marked on its graphs.
+
+Syntax:
+  grofer proc --name [NAME] --follow
+  grofer proc --match [REGEX]
+
+To stream the metrics as newline-delimited JSON instead of drawing a UI the -o or --output flag can be used.
+
+Syntax:
+  grofer proc -o jsonl
+
+To only list the processes whose PID, command name, command line or user
+matches a regular expression, or that are run by a user, the --filter and
+--user flags can be used.
+
+Syntax:
+  grofer proc --filter [REGEX] --user [USER]
+
+To choose and order the columns of the process table the --columns flag or
+the proc.columns key of the config file can be used.
+
+Syntax:
+  grofer proc --columns pid,user,cpu,rss,cmdline
+
+Signals and other actions are asked to be confirmed, and are refused for
+PID 1, grofer itself and processes whose command name or executable path
+matches one of the regular expressions of the proc.protected key of the
+config file. The command name is the one the kernel keeps, which is cut
+to 15 characters, not the full command line. To hide every action the
+--read-only flag can be used.
+
+Syntax:
+  grofer proc --read-only`,
 	Aliases: []string{"process", "processess"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// validate args and extract flags.
@@ -53,12 +105,21 @@ Syntax:
 			return err
 		}
 
+		output, err := procCmd.sinkOpts.openOutput()
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
 		// create a metric scraper factory that will help construct
 		// a process metric specific MetricScraper.
 		metricScraperFactory := factory.
 			NewMetricScraperFactory().
 			ForCommand(core.ProcCommand).
-			WithScrapeInterval(procCmd.refreshRate)
+			WithScrapeInterval(procCmd.refreshRate).
+			WithSinks(procCmd.sinkOpts.sinks...).
+			WithOutput(output).
+			WithAddress(procCmd.sinkOpts.metricsAddress)
 
 		if procCmd.isPerProcess() {
 			metricScraperFactory = metricScraperFactory.ForSingularEntity(procCmd.pid)
@@ -69,7 +130,20 @@ Syntax:
 			return err
 		}
 
-		err = processMetricScraper.Serve()
+		if procCmd.selector != nil {
+			err = processMetricScraper.Serve(
+				factory.WithProcessSelectorAs(procCmd.selector),
+				factory.WithFollowAs(procCmd.follow),
+			)
+		} else if procCmd.isPerProcess() {
+			err = processMetricScraper.Serve()
+		} else {
+			err = processMetricScraper.Serve(
+				factory.WithProcessFilterAs(procCmd.filter),
+				factory.WithProcessColumnsAs(procCmd.columns),
+				factory.WithProcessGuardAs(procCmd.guard),
+			)
+		}
 		if err != nil && err != core.ErrCanceledByUser {
 			if err == core.ErrInvalidPID {
 				utils.ErrorMsg("pid")
@@ -82,8 +156,14 @@ Syntax:
 }
 
 type procCommand struct {
+	sinkOpts    *sinkOptions
+	filter      *process.Filter
+	guard       *process.Guard
+	selector    *process.Selector
+	columns     []string
 	pid         string
 	refreshRate uint64
+	follow      bool
 }
 
 func constructProcCommand(cmd *cobra.Command, args []string) (*procCommand, error) {
@@ -99,18 +179,94 @@ func constructProcCommand(cmd *cobra.Command, args []string) (*procCommand, erro
 	if err != nil {
 		return nil, errors.New("error extracting --refresh flag")
 	}
-	if procRefreshRate < 1000 {
-		return nil, fmt.Errorf("invalid refresh rate: minimum refresh rate is 1000(ms)")
+	if procRefreshRate < minRefreshRate {
+		return nil, fmt.Errorf("invalid refresh rate: minimum refresh rate is %d(ms)", minRefreshRate)
+	}
+
+	pattern, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return nil, errors.New("error extracting --filter flag")
+	}
+	user, err := cmd.Flags().GetString("user")
+	if err != nil {
+		return nil, errors.New("error extracting --user flag")
+	}
+	if pids := strings.Split(pid, ","); len(pids) > processGraph.MaxCompared {
+		return nil, fmt.Errorf("at most %d processes can be compared", processGraph.MaxCompared)
+	}
+	if pid != defaultProcPid && (pattern != defaultProcFilter || user != defaultProcUser) {
+		return nil, errors.New("the --filter and --user flags cannot be used with --pid")
+	}
+	filter, err := process.NewFilter(pattern, user)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return nil, errors.New("error extracting --name flag")
+	}
+	match, err := cmd.Flags().GetString("match")
+	if err != nil {
+		return nil, errors.New("error extracting --match flag")
+	}
+	follow, err := cmd.Flags().GetBool("follow")
+	if err != nil {
+		return nil, errors.New("error extracting --follow flag")
+	}
+	var selector *process.Selector
+	if name != defaultProcName || match != defaultProcMatch {
+		if pid != defaultProcPid || pattern != defaultProcFilter || user != defaultProcUser {
+			return nil, errors.New("the --name and --match flags cannot be used with --pid, --filter or --user")
+		}
+		selector, err = process.NewSelector(name, match)
+		if err != nil {
+			return nil, err
+		}
+	} else if follow {
+		return nil, errors.New("the --follow flag requires --name or --match")
+	}
+
+	// the --columns flag takes precedence over the config file.
+	columns := viper.GetStringSlice("proc.columns")
+	if cmd.Flags().Changed("columns") {
+		columns, err = cmd.Flags().GetStringSlice("columns")
+		if err != nil {
+			return nil, errors.New("error extracting --columns flag")
+		}
+	}
+	if err := processGraph.CheckColumns(columns); err != nil {
+		return nil, err
+	}
+
+	readOnly, err := readOnlyFrom(cmd)
+	if err != nil {
+		return nil, err
+	}
+	guard, err := process.NewGuard(viper.GetStringSlice("proc.protected"), readOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	sinkOpts, err := constructSinkOptions(cmd)
+	if err != nil {
+		return nil, err
 	}
 
 	return &procCommand{
 		refreshRate: procRefreshRate,
 		pid:         pid,
+		filter:      filter,
+		guard:       guard,
+		selector:    selector,
+		follow:      follow,
+		columns:     columns,
+		sinkOpts:    sinkOpts,
 	}, nil
 }
 
 func (pc *procCommand) isPerProcess() bool {
-	return pc.pid != defaultProcPid
+	return pc.pid != defaultProcPid || pc.selector != nil
 }
 
 func init() {
@@ -120,13 +276,52 @@ func init() {
 		"refresh",
 		"r",
 		defaultProcRefreshRate,
-		"Process information UI refreshes rate in milliseconds greater than 1000",
+		"Process information UI refreshes rate in milliseconds greater than 200",
 	)
 
 	procCmd.Flags().StringP(
 		"pid",
 		"p",
 		defaultProcPid,
-		"specify PID of process. Passing PID 0 lists all the processes (same as not using the -p flag).",
+		"specify PID of process, or comma separated PIDs of processes to compare. Passing PID 0 lists all the processes (same as not using the -p flag).",
 	)
+
+	procCmd.Flags().String(
+		"filter",
+		defaultProcFilter,
+		"only list processes whose PID, command name, command line or user matches the regular expression.",
+	)
+
+	procCmd.Flags().String(
+		"user",
+		defaultProcUser,
+		"only list processes run by the user with the given name or ID.",
+	)
+
+	procCmd.Flags().String(
+		"name",
+		defaultProcName,
+		"attach to the newest process with the given command name or executable name.",
+	)
+
+	procCmd.Flags().String(
+		"match",
+		defaultProcMatch,
+		"attach to the newest process whose executable or command line matches the regular expression.",
+	)
+
+	procCmd.Flags().Bool(
+		"follow",
+		false,
+		"attach to the newest process matching --name or --match once the current one exits.",
+	)
+
+	procCmd.Flags().StringSlice(
+		"columns",
+		processGraph.DefaultColumns,
+		"comma separated columns of the process table, in order. One of: "+strings.Join(processGraph.ColumnNames(), ", ")+".",
+	)
+
+	addReadOnlyFlag(procCmd)
+	addSinkFlags(procCmd)
 }
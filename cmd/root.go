@@ -18,23 +18,39 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/factory"
+	"github.com/pesos/grofer/pkg/metrics/general"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 const (
 	defaultOverallRefreshRate = 1000
+	minRefreshRate            = 200
 	defaultConfigFileLocation = ""
 	defaultCPUBehavior        = false
+	defaultSink               = "tui"
+	defaultOutputFile         = ""
+	defaultMetricsAddress     = ":9184"
+	defaultReadOnly           = false
 )
 
 var cfgFile string
 
+// Maintain a map of sinks that can be selected using the
+// --output flag of commands that serve metrics.
+var providedSinks = map[string]core.Sink{
+	"tui":        core.TUI,
+	"jsonl":      core.JSONL,
+	"prometheus": core.Prometheus,
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "grofer",
@@ -49,18 +65,31 @@ While using a TUI based command, press ? to get information about key bindings (
 			return err
 		}
 
+		output, err := rootCmd.sinkOpts.openOutput()
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
 		// construct a system wide metric specific MetricScraper.
 		systemWideMetricScraper, err := factory.
 			NewMetricScraperFactory().
 			ForCommand(core.RootCommand).
 			WithScrapeInterval(rootCmd.refreshRate).
+			WithSinks(rootCmd.sinkOpts.sinks...).
+			WithOutput(output).
+			WithAddress(rootCmd.sinkOpts.metricsAddress).
 			Construct()
 
 		if err != nil {
 			return err
 		}
 
-		err = systemWideMetricScraper.Serve(factory.WithCPUInfoAs(rootCmd.cpuInfo))
+		err = systemWideMetricScraper.Serve(
+			factory.WithCPUInfoAs(rootCmd.cpuInfo),
+			withConfiguredCollectors(),
+			withConfiguredCollectorIntervals(),
+		)
 		if err != nil && err != core.ErrCanceledByUser {
 			fmt.Printf("Error: %v\n", err)
 		}
@@ -70,18 +99,188 @@ While using a TUI based command, press ? to get information about key bindings (
 }
 
 type rootCommand struct {
+	sinkOpts    *sinkOptions
 	refreshRate uint64
 	cpuInfo     bool
 }
 
+// sinkOptions holds the flags that select the sinks consuming the metrics
+// of a command, and where non-UI sinks write to.
+type sinkOptions struct {
+	outputFile     string
+	metricsAddress string
+	sinks          []core.Sink
+}
+
+func constructSinkOptions(cmd *cobra.Command) (*sinkOptions, error) {
+	sinkNames, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return nil, fmt.Errorf("error extracting --output flag")
+	}
+
+	sinks := []core.Sink{}
+	for _, sinkName := range strings.Split(sinkNames, ",") {
+		sinkName = strings.ToLower(strings.TrimSpace(sinkName))
+		sink, ok := providedSinks[sinkName]
+		if !ok {
+			return nil, fmt.Errorf("invalid output: %s, supported outputs are tui, jsonl and prometheus", sinkName)
+		}
+		for _, s := range sinks {
+			if s == sink {
+				return nil, fmt.Errorf("output %s specified more than once", sinkName)
+			}
+		}
+		sinks = append(sinks, sink)
+	}
+
+	outputFile, err := cmd.Flags().GetString("output-file")
+	if err != nil {
+		return nil, fmt.Errorf("error extracting --output-file flag")
+	}
+
+	metricsAddress, err := cmd.Flags().GetString("metrics-address")
+	if err != nil {
+		return nil, fmt.Errorf("error extracting --metrics-address flag")
+	}
+
+	hasSink := func(sink core.Sink) bool {
+		for _, s := range sinks {
+			if s == sink {
+				return true
+			}
+		}
+		return false
+	}
+
+	if outputFile != defaultOutputFile && !hasSink(core.JSONL) {
+		return nil, fmt.Errorf("--output-file can only be used with the jsonl output")
+	}
+
+	if outputFile == defaultOutputFile && hasSink(core.JSONL) && hasSink(core.TUI) {
+		return nil, fmt.Errorf("the jsonl output needs --output-file when used with the tui output")
+	}
+
+	return &sinkOptions{
+		sinks:          sinks,
+		outputFile:     outputFile,
+		metricsAddress: metricsAddress,
+	}, nil
+}
+
+// openOutput opens the file that metrics are written to, falling back to
+// stdout if no file was specified. The returned writer must be closed.
+func (so *sinkOptions) openOutput() (io.WriteCloser, error) {
+	if so.outputFile == defaultOutputFile {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.OpenFile(so.outputFile, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+}
+
+// withConfiguredCollectors returns an Option that enables and disables the
+// system wide metric collectors listed under the collectors key of the
+// config file, for example:
+//
+//	collectors:
+//	  disabled: [temp, battery]
+func withConfiguredCollectors() factory.Option {
+	return factory.WithCollectorsAs(
+		viper.GetStringSlice("collectors.enabled"),
+		viper.GetStringSlice("collectors.disabled"),
+	)
+}
+
+// withConfiguredCollectorIntervals returns an Option that sets the interval in
+// ms of the system wide metric collectors listed under the collectors.intervals
+// key of the config file, for example:
+//
+//	collectors:
+//	  intervals:
+//	    cpu: 500
+//	    disk: 10000
+func withConfiguredCollectorIntervals() factory.Option {
+	intervals := make(map[string]uint64)
+	for _, name := range general.DefaultRegistry.Names() {
+		key := "collectors.intervals." + name
+		if viper.IsSet(key) {
+			intervals[name] = viper.GetUint64(key)
+		}
+	}
+	return factory.WithCollectorIntervalsAs(intervals)
+}
+
+// checkCollectorIntervals returns an error if an interval set under the
+// collectors.intervals key of the config file is below the minimum refresh
+// rate, as the --refresh flag is checked.
+func checkCollectorIntervals() error {
+	for _, name := range general.DefaultRegistry.Names() {
+		key := "collectors.intervals." + name
+		if viper.IsSet(key) && viper.GetUint64(key) < minRefreshRate {
+			return fmt.Errorf("invalid interval of the %s collector: minimum refresh rate is %d(ms)", name, minRefreshRate)
+		}
+	}
+	return nil
+}
+
+// nopWriteCloser prevents stdout from being closed once a command is done.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// addSinkFlags adds the flags used to select a sink to a command.
+func addSinkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(
+		"output",
+		"o",
+		defaultSink,
+		"specify comma separated outputs that metrics are served to: tui, jsonl (newline-delimited JSON) or prometheus",
+	)
+
+	cmd.Flags().String(
+		"output-file",
+		defaultOutputFile,
+		"specify a file for the jsonl output to append metrics to (stdout by default)",
+	)
+
+	cmd.Flags().String(
+		"metrics-address",
+		defaultMetricsAddress,
+		"specify the address the prometheus output listens on",
+	)
+}
+
+// addReadOnlyFlag adds the flag hiding the actions of a command's UI.
+func addReadOnlyFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(
+		"read-only",
+		defaultReadOnly,
+		"hide every action that changes processes or containers, such as sending signals. Can also be set with the read-only key of the config file",
+	)
+}
+
+// readOnlyFrom returns whether the actions of a command's UI are hidden,
+// either by the --read-only flag or by the read-only key of the config file.
+func readOnlyFrom(cmd *cobra.Command) (bool, error) {
+	readOnly, err := cmd.Flags().GetBool("read-only")
+	if err != nil {
+		return false, fmt.Errorf("error extracting --read-only flag")
+	}
+	return readOnly || viper.GetBool("read-only"), nil
+}
+
 func constructRootCommand(cmd *cobra.Command, args []string) (*rootCommand, error) {
 	refreshRate, err := cmd.Flags().GetUint64("refresh")
 	if err != nil {
 		return nil, err
 	}
 
-	if refreshRate < 1000 {
-		return nil, fmt.Errorf("invalid refresh rate: minimum refresh rate is 1000(ms)")
+	if refreshRate < minRefreshRate {
+		return nil, fmt.Errorf("invalid refresh rate: minimum refresh rate is %d(ms)", minRefreshRate)
+	}
+
+	if err := checkCollectorIntervals(); err != nil {
+		return nil, err
 	}
 
 	cpuInfo, err := cmd.Flags().GetBool("cpuinfo")
@@ -89,9 +288,15 @@ func constructRootCommand(cmd *cobra.Command, args []string) (*rootCommand, erro
 		return nil, err
 	}
 
+	sinkOpts, err := constructSinkOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	return &rootCommand{
 		refreshRate: refreshRate,
 		cpuInfo:     cpuInfo,
+		sinkOpts:    sinkOpts,
 	}, nil
 }
 
@@ -115,7 +320,7 @@ func init() {
 		"refresh",
 		"r",
 		defaultOverallRefreshRate,
-		"Overall stats UI refreshes rate in milliseconds greater than 1000",
+		"Overall stats UI refreshes rate in milliseconds greater than 200",
 	)
 
 	rootCmd.Flags().BoolP(
@@ -124,6 +329,8 @@ func init() {
 		defaultCPUBehavior,
 		"Info about the CPU Load over all CPUs",
 	)
+
+	addSinkFlags(rootCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -148,6 +355,7 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		// written to stderr so that it does not mix with the jsonl output.
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
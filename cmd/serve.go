@@ -0,0 +1,176 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/factory"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultServeAddress              = ":9184"
+	defaultServeRefreshRate          = 1000
+	defaultServeProcRefreshRate      = 3000
+	defaultServeContainerRefreshRate = 1000
+	defaultServeContainers           = false
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve command is used to expose metrics to Prometheus",
+	Long: `serve command runs the system wide, process and container collectors and exposes the
+latest metrics at /metrics in the Prometheus text exposition format.
+
+Syntax:
+  grofer serve
+
+To also collect metrics about docker containers the --containers flag can be used.
+
+Syntax:
+  grofer serve --containers`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// validate args and extract flags.
+		serveCmd, err := constructServeCommand(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		// construct a MetricScraper that serves metrics over HTTP.
+		serveMetricScraper, err := factory.
+			NewMetricScraperFactory().
+			ForCommand(core.ServeCommand).
+			WithScrapeInterval(serveCmd.refreshRate).
+			WithAddress(serveCmd.address).
+			Construct()
+
+		if err != nil {
+			return err
+		}
+
+		err = serveMetricScraper.Serve(
+			factory.WithContainersAs(serveCmd.containers),
+			factory.WithProcRefreshRateAs(serveCmd.procRefreshRate),
+			factory.WithContainerRefreshRateAs(serveCmd.containerRefreshRate),
+			withConfiguredCollectors(),
+			withConfiguredCollectorIntervals(),
+		)
+		if err != nil && err != core.ErrCanceledByUser {
+			log.Printf("Error: %v\n", err)
+		}
+
+		return nil
+	},
+}
+
+type serveCommand struct {
+	address              string
+	refreshRate          uint64
+	procRefreshRate      uint64
+	containerRefreshRate uint64
+	containers           bool
+}
+
+func constructServeCommand(cmd *cobra.Command, args []string) (*serveCommand, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("the serve command should have no arguments, see grofer serve --help for further info")
+	}
+
+	address, err := cmd.Flags().GetString("address")
+	if err != nil {
+		return nil, errors.New("error extracting --address flag")
+	}
+
+	refreshRate, err := cmd.Flags().GetUint64("refresh")
+	if err != nil {
+		return nil, errors.New("error extracting --refresh flag")
+	}
+
+	procRefreshRate, err := cmd.Flags().GetUint64("proc-refresh")
+	if err != nil {
+		return nil, errors.New("error extracting --proc-refresh flag")
+	}
+
+	containerRefreshRate, err := cmd.Flags().GetUint64("container-refresh")
+	if err != nil {
+		return nil, errors.New("error extracting --container-refresh flag")
+	}
+
+	for _, rate := range []uint64{refreshRate, procRefreshRate, containerRefreshRate} {
+		if rate < minRefreshRate {
+			return nil, fmt.Errorf("invalid refresh rate: minimum refresh rate is %d(ms)", minRefreshRate)
+		}
+	}
+
+	if err := checkCollectorIntervals(); err != nil {
+		return nil, err
+	}
+
+	containers, err := cmd.Flags().GetBool("containers")
+	if err != nil {
+		return nil, errors.New("error extracting --containers flag")
+	}
+
+	return &serveCommand{
+		address:              address,
+		refreshRate:          refreshRate,
+		procRefreshRate:      procRefreshRate,
+		containerRefreshRate: containerRefreshRate,
+		containers:           containers,
+	}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringP(
+		"address",
+		"a",
+		defaultServeAddress,
+		"address to expose the /metrics endpoint on",
+	)
+
+	serveCmd.Flags().Uint64P(
+		"refresh",
+		"r",
+		defaultServeRefreshRate,
+		"System wide metrics scrape interval in milliseconds greater than 200",
+	)
+
+	serveCmd.Flags().Uint64(
+		"proc-refresh",
+		defaultServeProcRefreshRate,
+		"Process metrics scrape interval in milliseconds greater than 200",
+	)
+
+	serveCmd.Flags().Uint64(
+		"container-refresh",
+		defaultServeContainerRefreshRate,
+		"Container metrics scrape interval in milliseconds greater than 200",
+	)
+
+	serveCmd.Flags().Bool(
+		"containers",
+		defaultServeContainers,
+		"collect metrics about docker containers",
+	)
+}
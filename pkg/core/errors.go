@@ -25,8 +25,18 @@ var (
 	ErrCanceledByUser = errors.New("canceled by user")
 	// ErrInvalidPID is used when the user provided PID does not match a running process
 	ErrInvalidPID = errors.New("PID does not exist")
+	// ErrNoMatchingProcess is used when no running process matches the user provided name or regular expression
+	ErrNoMatchingProcess = errors.New("no running process matches")
 	// ErrInvalidContainer is used when the user provided Container ID does not match an existing container
 	ErrInvalidContainer = errors.New("container does not exist")
 	// ErrBatteryNotFound is used when the host does not have a `/sys/class/power_supply/BAT0` directory tor ead battery info from
 	ErrBatteryNotFound = errors.New("could not read from /sys/class/power_supply/BAT0")
+	// ErrUnsupportedSink is used when a command cannot serve its metrics to the requested sink
+	ErrUnsupportedSink = errors.New("sink not supported by this command")
+	// ErrUnknownCollector is used when a collector that is enabled or disabled is not registered
+	ErrUnknownCollector = errors.New("collector not registered")
+	// ErrUnknownColumn is used when a column of the process table that is configured does not exist
+	ErrUnknownColumn = errors.New("process table column does not exist")
+	// ErrProtectedProcess is used when an action is refused because the process is protected or grofer is read-only
+	ErrProtectedProcess = errors.New("process is protected")
 )
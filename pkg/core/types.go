@@ -30,6 +30,8 @@ const (
 	ContainerCommand
 	// ExportCommand is `grofer export` and its variants.
 	ExportCommand
+	// ServeCommand is `grofer serve` and its variants.
+	ServeCommand
 )
 
 // Sink represents any entity that consumes generated metrics.
@@ -43,8 +45,28 @@ const (
 	// TUI represents the terminal UI that consumes the metrics
 	// generated.
 	TUI Sink = iota
+	// JSONL represents a stream of newline-delimited JSON objects,
+	// one per sample, written to stdout or a file.
+	JSONL
+	// Prometheus represents an HTTP endpoint that exposes the
+	// latest metrics in the Prometheus text exposition format.
+	Prometheus
 )
 
+// String returns the name used to select the Sink, ex - on the command line.
+func (s Sink) String() string {
+	switch s {
+	case TUI:
+		return "tui"
+	case JSONL:
+		return "jsonl"
+	case Prometheus:
+		return "prometheus"
+	default:
+		return "unknown"
+	}
+}
+
 // Utility represents a utilty displayed in the UI
 type Utility int
 
@@ -53,10 +75,20 @@ const (
 	None Utility = iota
 	// Help is when the help box is displayed
 	Help
-	// Action is specific to `grofer container` and is used to select an action to perform
+	// Action is used to select an action to perform on a container or a process
 	Action
 	// Error is specific to `grofer container` and is used when an action fails/ times out
 	Error
 	// Kill is specific to `grofer proc` and is used to select a kill signal
 	Kill
+	// Filter is specific to `grofer proc` and is used while a filter is being typed
+	Filter
+	// Prompt is specific to `grofer proc` and is used while the input of an action is being typed
+	Prompt
+	// Confirm is used to ask for confirmation before a destructive action is performed
+	Confirm
+	// Details is specific to `grofer proc -p` and is used while the detail tabs of the process are displayed
+	Details
+	// Compare is specific to `grofer proc` and is used while the marked processes are compared
+	Compare
 )
@@ -23,128 +23,77 @@ import (
 	"strings"
 	"time"
 
-	cpuInfo "github.com/pesos/grofer/pkg/metrics/general"
-	"github.com/pesos/grofer/pkg/utils"
-	"github.com/shirou/gopsutil/cpu"
-	"github.com/shirou/gopsutil/disk"
-	"github.com/shirou/gopsutil/mem"
-	"github.com/shirou/gopsutil/net"
+	"github.com/pesos/grofer/pkg/metrics/general"
 )
 
-type diskStats struct {
-	Path     string  `json:"path"`
-	Fs       string  `json:"fs"`
-	Total    float64 `json:"total"`
-	Used     float64 `json:"used"`
-	UsedPerc float64 `json:"usedPerc"`
-	Free     float64 `json:"free"`
-}
-
-type netStats struct {
-	Sent float64 `json:"sent"`
-	Recv float64 `json:"recv"`
-}
-
-type memStats struct {
-	Total     float64 `json:"total"`
-	Available float64 `json:"available"`
-	Used      float64 `json:"used"`
-	Free      float64 `json:"free"`
-}
-
 // OverallStats describes the structure of each exported json object.
 type OverallStats struct {
-	NetStats  map[string]netStats `json:"net"`
-	CPUStats  []float64           `json:"cpu"`
-	DiskStats []diskStats         `json:"disk"`
-	CPULoad   cpuInfo.CPULoad     `json:"cpuLoad"`
-	MemStats  memStats            `json:"mem"`
-	Epoch     uint64              `json:"epoch"`
+	NetStats  map[string]general.NetStats `json:"net"`
+	CPUStats  []float64                   `json:"cpu"`
+	DiskStats []general.DiskStats         `json:"disk"`
+	CPULoad   general.CPULoad             `json:"cpuLoad"`
+	MemStats  general.MemoryStats         `json:"mem"`
+	Epoch     uint64                      `json:"epoch"`
+	// rates and cpuLoad keep the samples the rates of the next update
+	// are computed against.
+	rates   *general.RateSampler
+	cpuLoad *general.CPULoad
 }
 
 // NewOverallStats returns a pointer to an empty OverallStats struct
 func NewOverallStats() *OverallStats {
-	return &OverallStats{}
+	return &OverallStats{
+		rates:   general.NewRateSampler(),
+		cpuLoad: general.NewCPULoad(),
+	}
+}
+
+// prime takes the first samples of CPU times and of disk and network
+// counters, so that the first update reports the rates since then rather
+// than since boot for the CPU usage, or none for the transfer rates.
+func (data *OverallStats) prime() error {
+	if _, err := data.rates.CPURates(); err != nil {
+		return err
+	}
+	if _, err := data.rates.DiskStats(); err != nil {
+		return err
+	}
+	if _, err := data.rates.NetStats(); err != nil {
+		return err
+	}
+	return data.cpuLoad.UpdateCPULoad()
 }
 
 // updateData updates values of a received OverallStats struct, returns error on failure of updates
 func (data *OverallStats) updateData() error {
 	startUpdateTime := uint64(time.Now().Unix())
 
-	cpuRates, err := cpu.Percent(time.Second, true)
-	if err == nil {
-		for i, rate := range cpuRates {
-			cpuRates[i] = utils.RoundFloat(rate, "NONE", 2)
-		}
-		data.CPUStats = cpuRates
-	} else {
+	cpuRates, err := data.rates.CPURates()
+	if err != nil {
 		return err
 	}
+	data.CPUStats = cpuRates
 
-	// Memory values in giga
-	memory, err := mem.VirtualMemory()
-	memRates := memStats{
-		utils.RoundUint(memory.Total, "G", 2),
-		utils.RoundUint(memory.Available, "G", 2),
-		utils.RoundUint(memory.Used, "G", 2),
-		utils.RoundUint(memory.Free, "G", 2),
-	}
-
-	if err == nil {
-		data.MemStats = memRates
-	} else {
+	data.MemStats, err = general.GetMemStats()
+	if err != nil {
 		return err
 	}
 
-	// Disk values in giga
-	partitions, err := disk.Partitions(false)
-	if err == nil {
-		var tempParts []diskStats
-		for _, value := range partitions {
-			usageVals, _ := disk.Usage(value.Mountpoint)
-
-			if strings.HasPrefix(value.Device, "/dev/loop") {
-				continue
-			} else if strings.HasPrefix(value.Mountpoint, "/var/lib/docker") {
-				continue
-			} else {
-				path := usageVals.Path
-				total := utils.RoundUint(usageVals.Total, "G", 2)
-				used := utils.RoundUint(usageVals.Used, "G", 2)
-				usedPercent := utils.RoundFloat(usageVals.UsedPercent, "NONE", 2)
-				free := utils.RoundUint(usageVals.Free, "G", 2)
-				fs := usageVals.Fstype
-				temp := diskStats{path, fs, total, used, usedPercent, free}
-				tempParts = append(tempParts, temp)
-			}
-		}
-		data.DiskStats = tempParts
-	} else {
+	data.DiskStats, err = data.rates.DiskStats()
+	if err != nil {
 		return err
 	}
 
-	// Net values in kilo
-	netData, err := net.IOCounters(false)
-	if err == nil {
-		IO := make(map[string]netStats)
-		for _, IOStat := range netData {
-			nic := netStats{
-				utils.RoundUint(IOStat.BytesSent, "K", 2),
-				utils.RoundUint(IOStat.BytesRecv, "K", 2),
-			}
-			IO[IOStat.Name] = nic
-		}
-		data.NetStats = IO
-	} else {
+	data.NetStats, err = data.rates.NetStats()
+	if err != nil {
 		return err
 	}
 
-	cpuLoad := cpuInfo.NewCPULoad()
-	err = cpuLoad.UpdateCPULoad()
+	err = data.cpuLoad.UpdateCPULoad()
 	if err != nil {
 		return err
 	}
-	data.CPULoad = *cpuLoad
+	data.CPULoad = *data.cpuLoad
 
 	endUpdateTime := uint64(time.Now().Unix())
 	avg := uint64((startUpdateTime + endUpdateTime) / 2)
@@ -179,9 +128,15 @@ func ToJSON(filename string, iter uint32, refreshRate uint64) error {
 	// Encoder to encode JSON data into file
 	encoder := json.NewEncoder(logFile)
 	stats := NewOverallStats()
+	if err := stats.prime(); err != nil {
+		return err
+	}
 
-	// Encode JSON object by object into file
+	// Encode JSON object by object into file, every object measuring
+	// the CPU usage over the refresh rate before it
 	for i := uint32(0); i < iter; i++ {
+		time.Sleep(time.Duration(refreshRate) * time.Millisecond)
+
 		err := stats.updateData()
 		if err != nil {
 			fmt.Println("Error in iteration", i, "Error:", err)
@@ -191,8 +146,6 @@ func ToJSON(filename string, iter uint32, refreshRate uint64) error {
 				fmt.Println("Error in iteration", i, "Error:", err)
 			}
 		}
-
-		time.Sleep(time.Duration(refreshRate) * time.Millisecond)
 	}
 
 	return nil
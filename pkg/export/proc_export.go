@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pesos/grofer/pkg/core"
 	procInfo "github.com/pesos/grofer/pkg/metrics/process"
 	"github.com/pesos/grofer/pkg/utils"
 )
@@ -38,6 +39,11 @@ type pageFaults struct {
 	Major uint64 `json:"major"`
 }
 
+type ioPidStats struct {
+	Total     procInfo.IOStat `json:"total"`
+	PerSecond procInfo.IORate `json:"perSecond"`
+}
+
 type memPidStats struct {
 	RSS   uint64 `json:"RSS"`
 	Data  uint64 `json:"Data"`
@@ -63,6 +69,7 @@ type pidStats struct {
 	MemStats    memPidStats     `json:"memStats"`
 	CtxSwitches contextSwitches `json:"ctxSwitches"`
 	PageFaults  pageFaults      `json:"pageFaults"`
+	IO          *ioPidStats     `json:"io,omitempty"`
 	Mem         float64         `json:"mem"`
 	CPU         float64         `json:"cpu"`
 }
@@ -77,14 +84,9 @@ var statusMap = map[string]string{
 	"L": "Lock",
 }
 
-// getPidDataJSON returns a pidStats structure populated with information about the process specified by pid
-func getPidDataJSON(pid int32) (pidStats, error) {
-	proc, err := procInfo.NewProcess(pid)
-	if err != nil {
-		return pidStats{}, err
-	}
-	proc.UpdateProcInfo()
-
+// getPidDataJSON returns a pidStats structure populated with information about the given process.
+// The I/O rates are measured since the previous update of the process.
+func getPidDataJSON(proc *procInfo.Process) pidStats {
 	pidDetails := procDetails{
 		Name:              proc.Name,
 		Command:           proc.Exe,
@@ -122,7 +124,13 @@ func getPidDataJSON(pid int32) (pidStats, error) {
 		PageFaults:  pgFaults,
 		MemStats:    memStats,
 	}
-	return pidData, nil
+	if proc.IO != nil {
+		pidData.IO = &ioPidStats{
+			Total:     *proc.IO,
+			PerSecond: proc.IORate,
+		}
+	}
+	return pidData
 }
 
 // PidJSON exports data particular to a given process (given by pid) to a JSON
@@ -151,19 +159,28 @@ func PidJSON(pid int32, filename string, iter uint32, refreshRate uint64) error
 	// Encoder to encode JSON data into file
 	encoder := json.NewEncoder(logFile)
 
+	// The process is kept across iterations so that rates are measured
+	// over the refresh interval. It is sampled once beforehand, so that
+	// the first object has a CPU usage and I/O rates to report.
+	proc, err := procInfo.NewProcess(pid)
+	if err != nil {
+		return err
+	}
+	proc.UpdateProcInfo()
+
 	// Encode JSON object by object into file
 	for i := uint32(0); i < iter; i++ {
-		data, err := getPidDataJSON(pid)
-		if err != nil {
-			fmt.Println("Error in iteration", i, "Error:", err)
+		time.Sleep(time.Duration(refreshRate) * time.Millisecond)
+
+		proc.UpdateProcInfo()
+		if !proc.IsRunning {
+			fmt.Println("Error in iteration", i, "Error:", core.ErrInvalidPID)
 		} else {
-			err = encoder.Encode(data)
+			err = encoder.Encode(getPidDataJSON(proc))
 			if err != nil {
 				fmt.Println("Error in iteration", i, "Error:", err)
 			}
 		}
-
-		time.Sleep(time.Duration(refreshRate) * time.Millisecond)
 	}
 
 	return nil
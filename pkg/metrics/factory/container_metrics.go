@@ -24,16 +24,19 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/container"
+	"github.com/pesos/grofer/pkg/sink/jsonl"
+	"github.com/pesos/grofer/pkg/sink/prometheus"
 	"github.com/pesos/grofer/pkg/utils"
 	"golang.org/x/sync/errgroup"
 )
 
 type containerMetrics struct {
+	sinkConfig  // defaults to TUI.
 	client      *client.Client
-	all         bool
+	metricBus   *utils.Broadcaster
 	refreshRate uint64
-	sink        core.Sink // defaults to TUI.
-	metricBus   chan container.OverallMetrics
+	all         bool
+	readOnly    bool
 }
 
 // Serve serves metrics for all containers running on the system.
@@ -44,6 +47,34 @@ func (cms *containerMetrics) Serve(opts ...Option) error {
 	}
 	eg, ctx := errgroup.WithContext(context.Background())
 
+	// start consuming metrics.
+	for _, sink := range cms.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		dataChannel := make(chan container.OverallMetrics, bufferSize)
+		if _, err := cms.metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return containerGraph.OverallVisuals(ctx, cms.client, cms.all, cms.readOnly, dataChannel, cms.refreshRate)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.OverallContainerMetrics(ctx, dataChannel, cms.output)
+			})
+		case core.Prometheus:
+			exporter := prometheus.NewExporter()
+			eg.Go(func() error {
+				return exporter.ConsumeContainerMetrics(ctx, dataChannel)
+			})
+			eg.Go(func() error {
+				return prometheus.ListenAndServe(ctx, cms.address, exporter)
+			})
+		}
+	}
+
 	// start producing metrics.
 	eg.Go(func() error {
 		return utils.TickUntilDone(ctx, cms.refreshRate, func() error {
@@ -52,41 +83,22 @@ func (cms *containerMetrics) Serve(opts ...Option) error {
 				return err
 			}
 
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case cms.metricBus <- metrics:
-			}
-
-			return nil
+			return cms.metricBus.Publish(ctx, metrics)
 		})
 	})
 
-	// Start consuming metrics.
-	switch cms.sink {
-	case core.TUI:
-		eg.Go(func() error {
-			return containerGraph.OverallVisuals(ctx, cms.client, cms.all, cms.metricBus, cms.refreshRate)
-		})
-	}
-
 	return eg.Wait()
 }
 
-// SetSink sets the Sink for the produced metrics.
-func (cms *containerMetrics) SetSink(sink core.Sink) {
-	cms.sink = sink
-}
-
 // ensure interface compliance.
 var _ MetricScraper = (*containerMetrics)(nil)
 
 type singularContainerMetrics struct {
+	sinkConfig  // defaults to TUI.
 	client      *client.Client
-	refreshRate uint64
+	metricBus   *utils.Broadcaster
 	cid         string
-	sink        core.Sink // defaults to TUI.
-	metricBus   chan container.PerContainerMetrics
+	refreshRate uint64
 }
 
 // Serve serves metrics for a particular container running on the system.
@@ -97,6 +109,26 @@ func (scms *singularContainerMetrics) Serve(opts ...Option) error {
 	}
 	eg, ctx := errgroup.WithContext(context.Background())
 
+	// start consuming metrics.
+	for _, sink := range scms.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		dataChannel := make(chan container.PerContainerMetrics, bufferSize)
+		if _, err := scms.metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return containerGraph.PerContainerVisuals(ctx, dataChannel, scms.refreshRate)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.PerContainerMetrics(ctx, dataChannel, scms.output)
+			})
+		}
+	}
+
 	// start producing metrics.
 	eg.Go(func() error {
 		return utils.TickUntilDone(ctx, scms.refreshRate, func() error {
@@ -105,31 +137,12 @@ func (scms *singularContainerMetrics) Serve(opts ...Option) error {
 				return err
 			}
 
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case scms.metricBus <- metrics:
-			}
-
-			return nil
+			return scms.metricBus.Publish(ctx, metrics)
 		})
 	})
 
-	// Start consuming metrics.
-	switch scms.sink {
-	case core.TUI:
-		eg.Go(func() error {
-			return containerGraph.PerContainerVisuals(ctx, scms.metricBus, scms.refreshRate)
-		})
-	}
-
 	return eg.Wait()
 }
 
-// SetSink sets the Sink for the produced metrics.
-func (scms *singularContainerMetrics) SetSink(sink core.Sink) {
-	scms.sink = sink
-}
-
 // ensure interface compliance.
 var _ MetricScraper = (*singularContainerMetrics)(nil)
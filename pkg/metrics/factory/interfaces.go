@@ -30,4 +30,7 @@ type MetricScraper interface {
 	// SetSink sets the Sink that consumes the metrics produced
 	// by the MetricScraper.
 	SetSink(core.Sink)
+	// AddSink adds a Sink that consumes the metrics produced by
+	// the MetricScraper alongside the Sinks already set.
+	AddSink(core.Sink)
 }
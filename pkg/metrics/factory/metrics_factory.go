@@ -18,19 +18,29 @@ package factory
 
 import (
 	"errors"
+	"io"
+	"os"
 	"strconv"
-
-	proc "github.com/shirou/gopsutil/process"
+	"strings"
 
 	"github.com/docker/docker/client"
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/container"
 	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
 )
 
+// defaultAddress is the address that sinks serving metrics over
+// the network listen on by default.
+const defaultAddress = ":9184"
+
 // MetricScraperFactory constructs a MetricScaper for a command
 // and returns it.
 type MetricScraperFactory struct {
+	// output is where metrics are written to by sinks that
+	// do not draw a UI, such as core.JSONL. This defaults
+	// to os.Stdout.
+	output io.Writer
 	// command is the command for which a MetricScraper
 	// is created. This defaults to core.MainCommand.
 	command core.Command
@@ -45,6 +55,13 @@ type MetricScraperFactory struct {
 	// handled by an implementation of the MetricScraper
 	// interface.
 	entity string
+	// address is the address that sinks serving metrics
+	// over the network listen on.
+	address string
+	// sinks are the Sinks that consume the metrics scraped
+	// by the constructed MetricScraper. This defaults to
+	// the default Sink of the command.
+	sinks []core.Sink
 	// scrapeIntervalMillisecond is the frequency in ms at
 	// which metrics will be scraped.
 	scrapeIntervalMillisecond uint64
@@ -53,7 +70,10 @@ type MetricScraperFactory struct {
 // NewMetricScraperFactory is a constructor for the MetricScraperFactory type.
 // By default, this will be for the core.MainCommand command.
 func NewMetricScraperFactory() *MetricScraperFactory {
-	return &MetricScraperFactory{}
+	return &MetricScraperFactory{
+		output:  os.Stdout,
+		address: defaultAddress,
+	}
 }
 
 // ForCommand sets the command for which a MetricScraper needs to be constructed.
@@ -75,6 +95,25 @@ func (msf *MetricScraperFactory) WithScrapeInterval(interval uint64) *MetricScra
 	return msf
 }
 
+// WithSinks sets the Sinks that consume the metrics of the constructed MetricScraper.
+// All of them are fed from a single scrape.
+func (msf *MetricScraperFactory) WithSinks(sinks ...core.Sink) *MetricScraperFactory {
+	msf.sinks = sinks
+	return msf
+}
+
+// WithOutput sets the writer that non-UI sinks write metrics to.
+func (msf *MetricScraperFactory) WithOutput(output io.Writer) *MetricScraperFactory {
+	msf.output = output
+	return msf
+}
+
+// WithAddress sets the address that sinks serving metrics over the network listen on.
+func (msf *MetricScraperFactory) WithAddress(address string) *MetricScraperFactory {
+	msf.address = address
+	return msf
+}
+
 // Construct constructs the MetricScraper for a particular Command and returns it.
 func (msf *MetricScraperFactory) Construct() (MetricScraper, error) {
 	switch msf.command {
@@ -84,16 +123,50 @@ func (msf *MetricScraperFactory) Construct() (MetricScraper, error) {
 		return msf.constructContainerMetricScraper()
 	case core.ProcCommand:
 		return msf.constructProcessMetricScraper()
+	case core.ServeCommand:
+		return msf.constructServeMetricScraper()
 	}
 	return nil, errors.New("command not recognized")
 }
 
+// sinkConfigWithDefault returns the sinkConfig for the constructed MetricScraper,
+// using defaultSink if no Sinks were set on the factory.
+func (msf *MetricScraperFactory) sinkConfigWithDefault(defaultSink core.Sink) sinkConfig {
+	sinks := msf.sinks
+	if len(sinks) == 0 {
+		sinks = []core.Sink{defaultSink}
+	}
+	return sinkConfig{
+		output:  msf.output,
+		address: msf.address,
+		sinks:   sinks,
+	}
+}
+
 func (msf *MetricScraperFactory) constructSystemWideMetricScraper() (MetricScraper, error) {
+	sc := msf.sinkConfigWithDefault(core.TUI)
+	if err := validateSinks(sc.sinks, core.TUI, core.JSONL, core.Prometheus); err != nil {
+		return nil, err
+	}
 	return &systemWideMetrics{
+		sinkConfig:  sc,
 		refreshRate: msf.scrapeIntervalMillisecond,
 	}, nil
 }
 
+func (msf *MetricScraperFactory) constructServeMetricScraper() (MetricScraper, error) {
+	sc := msf.sinkConfigWithDefault(core.Prometheus)
+	if err := validateSinks(sc.sinks, core.JSONL, core.Prometheus); err != nil {
+		return nil, err
+	}
+	return &serveMetrics{
+		sinkConfig:           sc,
+		refreshRate:          msf.scrapeIntervalMillisecond,
+		procRefreshRate:      msf.scrapeIntervalMillisecond,
+		containerRefreshRate: msf.scrapeIntervalMillisecond,
+	}, nil
+}
+
 func (msf *MetricScraperFactory) constructContainerMetricScraper() (MetricScraper, error) {
 	if msf.singularEntityMetrics {
 		return msf.newSingluarContainerMetrics()
@@ -102,29 +175,39 @@ func (msf *MetricScraperFactory) constructContainerMetricScraper() (MetricScrape
 }
 
 func (msf *MetricScraperFactory) newContainerMetrics() (*containerMetrics, error) {
+	sc := msf.sinkConfigWithDefault(core.TUI)
+	if err := validateSinks(sc.sinks, core.TUI, core.JSONL, core.Prometheus); err != nil {
+		return nil, err
+	}
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, err
 	}
 	cms := &containerMetrics{
+		sinkConfig:  sc,
 		client:      cli,
 		refreshRate: msf.scrapeIntervalMillisecond,
-		metricBus:   make(chan container.OverallMetrics),
+		metricBus:   utils.NewBroadcaster(container.OverallMetrics{}),
 	}
 
 	return cms, nil
 }
 
 func (msf *MetricScraperFactory) newSingluarContainerMetrics() (*singularContainerMetrics, error) {
+	sc := msf.sinkConfigWithDefault(core.TUI)
+	if err := validateSinks(sc.sinks, core.TUI, core.JSONL); err != nil {
+		return nil, err
+	}
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, err
 	}
 	scms := &singularContainerMetrics{
+		sinkConfig:  sc,
 		client:      cli,
 		refreshRate: msf.scrapeIntervalMillisecond,
 		cid:         msf.entity,
-		metricBus:   make(chan container.PerContainerMetrics, 1),
+		metricBus:   utils.NewBroadcaster(container.PerContainerMetrics{}),
 	}
 
 	return scms, nil
@@ -132,30 +215,77 @@ func (msf *MetricScraperFactory) newSingluarContainerMetrics() (*singularContain
 
 func (msf *MetricScraperFactory) constructProcessMetricScraper() (MetricScraper, error) {
 	if msf.singularEntityMetrics {
+		if strings.Contains(msf.entity, ",") {
+			return msf.newComparedProcessMetrics()
+		}
 		return msf.newSingluarProcessMetrics()
 	}
 	return msf.newProcessMetrics()
 }
 
 func (msf *MetricScraperFactory) newProcessMetrics() (*processMetrics, error) {
+	sc := msf.sinkConfigWithDefault(core.TUI)
+	if err := validateSinks(sc.sinks, core.TUI, core.JSONL, core.Prometheus); err != nil {
+		return nil, err
+	}
 	pm := &processMetrics{
+		sinkConfig:  sc,
 		refreshRate: msf.scrapeIntervalMillisecond,
-		metricBus:   make(chan []*proc.Process, 1),
+		metricBus:   utils.NewBroadcaster([]process.Snapshot{}),
+		sampler:     process.NewSampler(),
 	}
 
 	return pm, nil
 }
 
 func (msf *MetricScraperFactory) newSingluarProcessMetrics() (*singularProcessMetrics, error) {
-	pid, err := strconv.ParseInt(msf.entity, 10, 32)
-	if err != nil {
+	sc := msf.sinkConfigWithDefault(core.TUI)
+	if err := validateSinks(sc.sinks, core.TUI, core.JSONL); err != nil {
 		return nil, err
 	}
+	// the process is looked up by a selector if there is no PID.
+	pid := int64(0)
+	if msf.entity != "" {
+		var err error
+		pid, err = strconv.ParseInt(msf.entity, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+	}
 	spm := &singularProcessMetrics{
+		sinkConfig:  sc,
 		refreshRate: msf.scrapeIntervalMillisecond,
-		metricBus:   make(chan *process.Process, 1),
+		metricBus:   utils.NewBroadcaster(&process.Process{}),
 		pid:         int32(pid),
 	}
 
 	return spm, nil
 }
+
+func (msf *MetricScraperFactory) newComparedProcessMetrics() (*comparedProcessMetrics, error) {
+	sc := msf.sinkConfigWithDefault(core.TUI)
+	if err := validateSinks(sc.sinks, core.TUI, core.JSONL); err != nil {
+		return nil, err
+	}
+	// the entity is a comma separated list of PIDs, each compared once.
+	var pids []int32
+	seen := make(map[int32]bool)
+	for _, field := range strings.Split(msf.entity, ",") {
+		pid, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[int32(pid)] {
+			seen[int32(pid)] = true
+			pids = append(pids, int32(pid))
+		}
+	}
+	cpm := &comparedProcessMetrics{
+		sinkConfig:  sc,
+		refreshRate: msf.scrapeIntervalMillisecond,
+		metricBus:   utils.NewBroadcaster([]*process.Process{}),
+		pids:        pids,
+	}
+
+	return cpm, nil
+}
@@ -16,6 +16,12 @@ limitations under the License.
 
 package factory
 
+import (
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
 // Option is used to inject command specific configuration.
 type Option func(MetricScraper)
 
@@ -34,3 +40,114 @@ func WithCPUInfoAs(cpuInfo bool) Option {
 		swm.cpuInfo = cpuInfo
 	}
 }
+
+// WithContainersAs sets whether container metrics are collected for the ServeCommand.
+func WithContainersAs(containers bool) Option {
+	return func(ms MetricScraper) {
+		sm := ms.(*serveMetrics)
+		sm.containers = containers
+	}
+}
+
+// WithProcRefreshRateAs sets the process scrape interval in ms for the ServeCommand.
+func WithProcRefreshRateAs(refreshRate uint64) Option {
+	return func(ms MetricScraper) {
+		sm := ms.(*serveMetrics)
+		sm.procRefreshRate = refreshRate
+	}
+}
+
+// WithContainerRefreshRateAs sets the container scrape interval in ms for the ServeCommand.
+func WithContainerRefreshRateAs(refreshRate uint64) Option {
+	return func(ms MetricScraper) {
+		sm := ms.(*serveMetrics)
+		sm.containerRefreshRate = refreshRate
+	}
+}
+
+// WithProcessFilterAs sets the filter selecting the processes that are
+// scraped for the ProcCommand.
+func WithProcessFilterAs(filter *process.Filter) Option {
+	return func(ms MetricScraper) {
+		pm := ms.(*processMetrics)
+		pm.filter = filter
+	}
+}
+
+// WithProcessColumnsAs sets the columns of the process table, in order,
+// for the ProcCommand.
+func WithProcessColumnsAs(columns []string) Option {
+	return func(ms MetricScraper) {
+		pm := ms.(*processMetrics)
+		pm.columns = columns
+	}
+}
+
+// WithProcessGuardAs sets the guard deciding which processes may be acted
+// upon from the process table for the ProcCommand.
+func WithProcessGuardAs(guard *process.Guard) Option {
+	return func(ms MetricScraper) {
+		pm := ms.(*processMetrics)
+		pm.guard = guard
+	}
+}
+
+// WithProcessSelectorAs sets the selector looking up the process for the
+// ProcCommand, in place of a PID.
+func WithProcessSelectorAs(selector *process.Selector) Option {
+	return func(ms MetricScraper) {
+		spm := ms.(*singularProcessMetrics)
+		spm.selector = selector
+	}
+}
+
+// WithFollowAs sets whether the newest process matching the selector is
+// followed once the current one exits for the ProcCommand.
+func WithFollowAs(follow bool) Option {
+	return func(ms MetricScraper) {
+		spm := ms.(*singularProcessMetrics)
+		spm.follow = follow
+	}
+}
+
+// WithReadOnlyAs sets whether the container actions are hidden for the
+// ContainerCommand.
+func WithReadOnlyAs(readOnly bool) Option {
+	return func(ms MetricScraper) {
+		cms := ms.(*containerMetrics)
+		cms.readOnly = readOnly
+	}
+}
+
+// WithCollectorsAs sets the system wide metric collectors that are enabled and
+// disabled for the RootCommand and the ServeCommand.
+func WithCollectorsAs(enabled, disabled []string) Option {
+	return func(ms MetricScraper) {
+		switch ms := ms.(type) {
+		case *systemWideMetrics:
+			ms.enabledCollectors = enabled
+			ms.disabledCollectors = disabled
+		case *serveMetrics:
+			ms.enabledCollectors = enabled
+			ms.disabledCollectors = disabled
+		}
+	}
+}
+
+// WithCollectorIntervalsAs sets the interval in ms of system wide metric collectors,
+// keyed by their name, for the RootCommand and the ServeCommand.
+func WithCollectorIntervalsAs(intervals map[string]uint64) Option {
+	return func(ms MetricScraper) {
+		collectorIntervals := make(map[string]time.Duration, len(intervals))
+		for name, interval := range intervals {
+			collectorIntervals[name] = time.Duration(interval) * time.Millisecond
+		}
+
+		switch ms := ms.(type) {
+		case *systemWideMetrics:
+			ms.collectorIntervals = collectorIntervals
+		case *serveMetrics:
+			ms.collectorIntervals = collectorIntervals
+		}
+	}
+}
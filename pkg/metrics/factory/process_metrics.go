@@ -18,19 +18,31 @@ package factory
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/sink/jsonl"
+	"github.com/pesos/grofer/pkg/sink/prometheus"
 	processGraph "github.com/pesos/grofer/pkg/sink/tui/process"
 	"github.com/pesos/grofer/pkg/utils"
-	proc "github.com/shirou/gopsutil/process"
 	"golang.org/x/sync/errgroup"
 )
 
+// eventLogSize is the number of process start and exit events kept for
+// the sinks.
+const eventLogSize = 1000
+
 type processMetrics struct {
+	sinkConfig  // defaults to TUI.
+	metricBus   *utils.Broadcaster
+	sampler     *process.Sampler
+	filter      *process.Filter
+	events      *process.EventLog
+	columns     []string
+	guard       *process.Guard
 	refreshRate uint64
-	sink        core.Sink // defaults to TUI.
-	metricBus   chan []*proc.Process
 }
 
 // Serve serves metrics of all processes running in the system.
@@ -39,51 +51,64 @@ func (pm *processMetrics) Serve(opts ...Option) error {
 	for _, opt := range opts {
 		opt(pm)
 	}
+	pm.events = process.NewEventLog(eventLogSize, pm.filter)
 	eg, ctx := errgroup.WithContext(context.Background())
 
+	// start consuming metrics.
+	for _, sink := range pm.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		dataChannel := make(chan []process.Snapshot, bufferSize)
+		if _, err := pm.metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return processGraph.AllProcVisuals(ctx, dataChannel, pm.events, pm.refreshRate, pm.columns, pm.filter, pm.guard)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.AllProcs(ctx, dataChannel, pm.events, pm.output)
+			})
+		case core.Prometheus:
+			exporter := prometheus.NewExporter()
+			eg.Go(func() error {
+				return exporter.ConsumeProcs(ctx, dataChannel)
+			})
+			eg.Go(func() error {
+				return prometheus.ListenAndServe(ctx, pm.address, exporter)
+			})
+		}
+	}
+
 	// start producing metrics.
 	eg.Go(func() error {
 		alteredRefreshRate := uint64(4 * pm.refreshRate / 5)
 		return utils.TickUntilDone(ctx, alteredRefreshRate, func() error {
-			procs, err := proc.Processes()
+			procs, err := pm.sampler.Snapshot()
 			if err != nil {
 				return err
 			}
+			pm.events.Observe(procs, time.Now())
 
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case pm.metricBus <- procs:
-			}
-
-			return err
+			return pm.metricBus.Publish(ctx, pm.filter.Apply(procs))
 		})
 	})
 
-	// Start consuming metrics.
-	switch pm.sink {
-	case core.TUI:
-		eg.Go(func() error {
-			return processGraph.AllProcVisuals(ctx, pm.metricBus, pm.refreshRate)
-		})
-	}
-
 	return eg.Wait()
 }
 
-// SetSink sets the sink that consumes the produced metrics.
-func (pm *processMetrics) SetSink(sink core.Sink) {
-	pm.sink = sink
-}
-
 // ensure interface compliance.
 var _ MetricScraper = (*processMetrics)(nil)
 
 type singularProcessMetrics struct {
-	pid         int32
+	sinkConfig  // defaults to TUI.
+	metricBus   *utils.Broadcaster
+	selector    *process.Selector
 	refreshRate uint64
-	sink        core.Sink // defaults to TUI.
-	metricBus   chan *process.Process
+	pid         int32
+	follow      bool
 }
 
 // Serve serves metrics of a particular process.
@@ -94,42 +119,143 @@ func (spm *singularProcessMetrics) Serve(opts ...Option) error {
 	}
 	eg, ctx := errgroup.WithContext(context.Background())
 
-	process, err := process.NewProcess(spm.pid)
-	if err != nil {
-		return core.ErrInvalidPID
+	var p *process.Process
+	var err error
+	if spm.selector != nil {
+		p, err = spm.attach()
+		if err != nil {
+			return err
+		}
+	} else {
+		p, err = process.NewProcess(spm.pid)
+		if err != nil {
+			return core.ErrInvalidPID
+		}
+	}
+
+	// the sinks turn on the costly readings they show.
+	readings := &process.Readings{}
+	p.Readings = readings
+
+	// start consuming metrics.
+	for _, sink := range spm.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		dataChannel := make(chan *process.Process, bufferSize)
+		if _, err := spm.metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return processGraph.ProcVisuals(ctx, dataChannel, spm.refreshRate, readings)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.PerProc(ctx, dataChannel, spm.output)
+			})
+		}
 	}
 
 	// start producing metrics.
 	eg.Go(func() error {
 		alteredRefreshRate := uint64(4 * spm.refreshRate / 5)
 		return utils.TickUntilDone(ctx, alteredRefreshRate, func() error {
-			process.UpdateProcInfo()
-
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case spm.metricBus <- process:
+			p.UpdateProcInfo()
+			if spm.follow && (!p.IsRunning || p.Status == "Z") {
+				// keep showing the exited process until another matches.
+				if next, err := spm.attach(); err == nil {
+					p = next
+					p.Readings = readings
+					p.UpdateProcInfo()
+				}
 			}
-
-			return err
+			// publish a copy so that sinks do not race with the next update.
+			return spm.metricBus.Publish(ctx, p.Copy())
 		})
 	})
 
-	// start consuming metrics.
-	switch spm.sink {
-	case core.TUI:
-		eg.Go(func() error {
-			return processGraph.ProcVisuals(ctx, spm.metricBus, spm.refreshRate)
-		})
+	return eg.Wait()
+}
+
+// attach returns the newest process matching the selector. Exited
+// processes that are not reaped yet are not matched.
+func (spm *singularProcessMetrics) attach() (*process.Process, error) {
+	procs, err := process.NewSampler().Snapshot()
+	if err != nil {
+		return nil, err
 	}
 
-	return eg.Wait()
+	newest, ok := spm.selector.Newest(procs)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", core.ErrNoMatchingProcess, spm.selector)
+	}
+	return process.NewProcess(newest.PID)
 }
 
-// SetSink sets the sink that consumes the produced metrics.
-func (spm *singularProcessMetrics) SetSink(sink core.Sink) {
-	spm.sink = sink
+// ensure interface compliance.
+var _ MetricScraper = (*singularProcessMetrics)(nil)
+
+type comparedProcessMetrics struct {
+	sinkConfig  // defaults to TUI.
+	metricBus   *utils.Broadcaster
+	refreshRate uint64
+	pids        []int32
+}
+
+// Serve serves metrics of the processes being compared.
+func (cpm *comparedProcessMetrics) Serve(opts ...Option) error {
+	// apply command specific options.
+	for _, opt := range opts {
+		opt(cpm)
+	}
+	eg, ctx := errgroup.WithContext(context.Background())
+
+	procs := make([]*process.Process, 0, len(cpm.pids))
+	for _, pid := range cpm.pids {
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			return core.ErrInvalidPID
+		}
+		procs = append(procs, p)
+	}
+
+	// start consuming metrics.
+	for _, sink := range cpm.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		dataChannel := make(chan []*process.Process, bufferSize)
+		if _, err := cpm.metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return processGraph.CompareVisuals(ctx, dataChannel, cpm.refreshRate)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.ComparedProcs(ctx, dataChannel, cpm.output)
+			})
+		}
+	}
+
+	// start producing metrics.
+	eg.Go(func() error {
+		alteredRefreshRate := uint64(4 * cpm.refreshRate / 5)
+		return utils.TickUntilDone(ctx, alteredRefreshRate, func() error {
+			// publish copies so that sinks do not race with the next update.
+			copies := make([]*process.Process, 0, len(procs))
+			for _, p := range procs {
+				p.UpdateProcInfo()
+				copies = append(copies, p.Copy())
+			}
+			return cpm.metricBus.Publish(ctx, copies)
+		})
+	})
+
+	return eg.Wait()
 }
 
 // ensure interface compliance.
-var _ MetricScraper = (*singularContainerMetrics)(nil)
+var _ MetricScraper = (*comparedProcessMetrics)(nil)
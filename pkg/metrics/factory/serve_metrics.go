@@ -0,0 +1,171 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/container"
+	"github.com/pesos/grofer/pkg/metrics/general"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/sink/jsonl"
+	"github.com/pesos/grofer/pkg/sink/prometheus"
+	"github.com/pesos/grofer/pkg/utils"
+	"golang.org/x/sync/errgroup"
+)
+
+type serveMetrics struct {
+	sinkConfig // defaults to Prometheus.
+	// enabledCollectors and disabledCollectors select the
+	// registered collectors that are run, all are run if
+	// neither is set.
+	enabledCollectors  []string
+	disabledCollectors []string
+	// collectorIntervals overrides the interval of
+	// collectors keyed by their name.
+	collectorIntervals   map[string]time.Duration
+	refreshRate          uint64
+	procRefreshRate      uint64
+	containerRefreshRate uint64
+	containers           bool
+}
+
+// Serve runs the system wide, process and (optionally) container collectors
+// on their own schedules and serves the latest metrics they produce.
+func (sm *serveMetrics) Serve(opts ...Option) error {
+	// apply command specific options.
+	for _, opt := range opts {
+		opt(sm)
+	}
+	collectors, err := general.DefaultRegistry.Select(sm.enabledCollectors, sm.disabledCollectors)
+	if err != nil {
+		return err
+	}
+	refreshRate := time.Duration(sm.refreshRate) * time.Millisecond
+	scheduled := general.Schedule(collectors, refreshRate, sm.collectorIntervals)
+
+	eg, ctx := errgroup.WithContext(context.Background())
+
+	systemBus := newSystemBus()
+	procBus := utils.NewBroadcaster([]process.Snapshot{})
+	procEvents := process.NewEventLog(eventLogSize, nil)
+	containerBus := utils.NewBroadcaster(container.OverallMetrics{})
+
+	// start consuming metrics.
+	for _, sink := range sm.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		systemBufferSize, _ := systemSubscriptionFor(sink, len(scheduled))
+		systemChannel := make(chan general.AggregatedMetrics, systemBufferSize)
+		procChannel := make(chan []process.Snapshot, bufferSize)
+		containerChannel := make(chan container.OverallMetrics, bufferSize)
+		if _, err := systemBus.Subscribe(systemChannel, policy); err != nil {
+			return err
+		}
+		if _, err := procBus.Subscribe(procChannel, policy); err != nil {
+			return err
+		}
+		if _, err := containerBus.Subscribe(containerChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.SystemWideMetrics(ctx, systemChannel, sm.output)
+			})
+			eg.Go(func() error {
+				return jsonl.AllProcs(ctx, procChannel, procEvents, sm.output)
+			})
+			eg.Go(func() error {
+				return jsonl.OverallContainerMetrics(ctx, containerChannel, sm.output)
+			})
+		case core.Prometheus:
+			exporter := prometheus.NewExporter()
+			eg.Go(func() error {
+				return exporter.ConsumeSystemWideMetrics(ctx, systemChannel)
+			})
+			eg.Go(func() error {
+				return exporter.ConsumeProcs(ctx, procChannel)
+			})
+			eg.Go(func() error {
+				return exporter.ConsumeContainerMetrics(ctx, containerChannel)
+			})
+			eg.Go(func() error {
+				return prometheus.ListenAndServe(ctx, sm.address, exporter)
+			})
+		}
+	}
+
+	// start producing system wide metrics.
+	scraped := make(chan general.AggregatedMetrics, 1)
+	eg.Go(func() error {
+		return general.GlobalStats(ctx, scheduled, scraped)
+	})
+	eg.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case metrics := <-scraped:
+				if err := systemBus.Publish(ctx, metrics); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	// start producing process metrics.
+	sampler := process.NewSampler()
+	eg.Go(func() error {
+		return utils.TickUntilDone(ctx, sm.procRefreshRate, func() error {
+			procs, err := sampler.Snapshot()
+			if err != nil {
+				return err
+			}
+			procEvents.Observe(procs, time.Now())
+
+			return procBus.Publish(ctx, procs)
+		})
+	})
+
+	// start producing container metrics.
+	if sm.containers {
+		cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+		if err != nil {
+			return err
+		}
+
+		eg.Go(func() error {
+			return utils.TickUntilDone(ctx, sm.containerRefreshRate, func() error {
+				metrics, err := container.GetOverallMetrics(ctx, cli, false)
+				if err != nil {
+					return err
+				}
+
+				return containerBus.Publish(ctx, metrics)
+			})
+		})
+	}
+
+	return eg.Wait()
+}
+
+// ensure interface compliance.
+var _ MetricScraper = (*serveMetrics)(nil)
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/general"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+// sinkConfig holds the Sinks that consume the metrics produced by a
+// MetricScraper along with the configuration they share. It is embedded
+// by every MetricScraper.
+type sinkConfig struct {
+	// output is where sinks that do not draw a UI, such
+	// as core.JSONL, write metrics to.
+	output io.Writer
+	// address is the address that sinks serving metrics
+	// over the network, such as core.Prometheus, listen on.
+	address string
+	sinks   []core.Sink
+}
+
+// SetSink sets the Sink for the produced metrics, replacing any others.
+func (sc *sinkConfig) SetSink(sink core.Sink) {
+	sc.sinks = []core.Sink{sink}
+}
+
+// AddSink adds a Sink that consumes the produced metrics alongside the
+// existing ones.
+func (sc *sinkConfig) AddSink(sink core.Sink) {
+	sc.sinks = append(sc.sinks, sink)
+}
+
+// validateSinks returns core.ErrUnsupportedSink if any Sink in sinks is
+// not one of the supported Sinks.
+func validateSinks(sinks []core.Sink, supported ...core.Sink) error {
+	for _, sink := range sinks {
+		isSupported := false
+		for _, s := range supported {
+			isSupported = isSupported || s == sink
+		}
+		if !isSupported {
+			return fmt.Errorf("%w: %v", core.ErrUnsupportedSink, sink)
+		}
+	}
+	return nil
+}
+
+// subscriptionFor returns the buffer size and drop policy that a Sink
+// subscribes to a metric bus with. No Sink blocks the bus, so a slow
+// Sink cannot stall the others.
+func subscriptionFor(sink core.Sink) (int, utils.DropPolicy) {
+	switch sink {
+	case core.JSONL:
+		// absorb bursts while the output is slow, and drop
+		// new samples rather than reorder them once full.
+		return 64, utils.DropNewest
+	default:
+		// the TUI and exporters only care about the latest sample.
+		return 1, utils.DropOldest
+	}
+}
+
+// newSystemBus returns the metric bus of system wide metrics, which carries
+// one partial sample per collector keyed by its FieldSet.
+func newSystemBus() *utils.Broadcaster {
+	return utils.NewBroadcaster(general.AggregatedMetrics{}).WithKey(func(sample interface{}) string {
+		return sample.(general.AggregatedMetrics).FieldSet
+	})
+}
+
+// systemSubscriptionFor is subscriptionFor on the bus of system wide
+// metrics run by the given number of collectors. Sinks that only care
+// about the latest sample keep the latest sample of every collector, so
+// that the samples of collectors run every few seconds are not pushed
+// out by those of collectors run at every refresh.
+func systemSubscriptionFor(sink core.Sink, collectors int) (int, utils.DropPolicy) {
+	bufferSize, policy := subscriptionFor(sink)
+	if policy == utils.DropOldest && bufferSize < collectors {
+		bufferSize = collectors
+	}
+	return bufferSize, policy
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/general"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestSystemBusKeepsEveryFieldSet(t *testing.T) {
+	ctx := context.Background()
+	bus := newSystemBus()
+
+	// the TUI lags behind while the CPU collector runs again.
+	bufferSize, policy := systemSubscriptionFor(core.TUI, 2)
+	samples := make(chan general.AggregatedMetrics, bufferSize)
+	_, err := bus.Subscribe(samples, policy)
+	utils.Raises(t, err)
+
+	for _, fieldSet := range []string{"CPU", "DISK", "CPU"} {
+		utils.Raises(t, bus.Publish(ctx, general.AggregatedMetrics{FieldSet: fieldSet}))
+	}
+	utils.Equals(t, 2, len(samples))
+	utils.Equals(t, "DISK", (<-samples).FieldSet)
+	utils.Equals(t, "CPU", (<-samples).FieldSet)
+}
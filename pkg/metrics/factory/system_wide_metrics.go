@@ -18,17 +18,29 @@ package factory
 
 import (
 	"context"
+	"time"
 
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/general"
+	"github.com/pesos/grofer/pkg/sink/jsonl"
+	"github.com/pesos/grofer/pkg/sink/prometheus"
 	overallGraph "github.com/pesos/grofer/pkg/sink/tui/general"
+	"github.com/pesos/grofer/pkg/utils"
 	"golang.org/x/sync/errgroup"
 )
 
 type systemWideMetrics struct {
-	cpuInfo     bool
-	sink        core.Sink // defaults to TUI.
-	refreshRate uint64
+	sinkConfig // defaults to TUI.
+	// enabledCollectors and disabledCollectors select the
+	// registered collectors that are run, all are run if
+	// neither is set.
+	enabledCollectors  []string
+	disabledCollectors []string
+	// collectorIntervals overrides the interval of
+	// collectors keyed by their name.
+	collectorIntervals map[string]time.Duration
+	refreshRate        uint64
+	cpuInfo            bool
 }
 
 // Serve serves system wide metrics.
@@ -47,22 +59,61 @@ func (swm *systemWideMetrics) Serve(opts ...Option) error {
 // serveGenericMetrics serves generic metrics such as metrics related to
 // network, memory, CPU etc.
 func (swm *systemWideMetrics) serveGenericMetrics() error {
+	collectors, err := general.DefaultRegistry.Select(swm.enabledCollectors, swm.disabledCollectors)
+	if err != nil {
+		return err
+	}
+	refreshRate := time.Duration(swm.refreshRate) * time.Millisecond
+	scheduled := general.Schedule(collectors, refreshRate, swm.collectorIntervals)
+
 	eg, ctx := errgroup.WithContext(context.Background())
-	metricBus := make(chan general.AggregatedMetrics, 1)
+	metricBus := newSystemBus()
+
+	// start consuming metrics.
+	for _, sink := range swm.sinks {
+		bufferSize, policy := systemSubscriptionFor(sink, len(scheduled))
+		dataChannel := make(chan general.AggregatedMetrics, bufferSize)
+		if _, err := metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return overallGraph.RenderCharts(ctx, dataChannel, swm.refreshRate)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.SystemWideMetrics(ctx, dataChannel, swm.output)
+			})
+		case core.Prometheus:
+			exporter := prometheus.NewExporter()
+			eg.Go(func() error {
+				return exporter.ConsumeSystemWideMetrics(ctx, dataChannel)
+			})
+			eg.Go(func() error {
+				return prometheus.ListenAndServe(ctx, swm.address, exporter)
+			})
+		}
+	}
 
 	// start producing metrics.
+	scraped := make(chan general.AggregatedMetrics, 1)
 	eg.Go(func() error {
-		alteredRefreshRate := uint64(4 * swm.refreshRate / 5)
-		return general.GlobalStats(ctx, metricBus, alteredRefreshRate)
+		return general.GlobalStats(ctx, scheduled, scraped)
+	})
+	eg.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case metrics := <-scraped:
+				if err := metricBus.Publish(ctx, metrics); err != nil {
+					return err
+				}
+			}
+		}
 	})
-
-	// start consuming metrics.
-	switch swm.sink {
-	case core.TUI:
-		eg.Go(func() error {
-			return overallGraph.RenderCharts(ctx, metricBus, swm.refreshRate)
-		})
-	}
 
 	return eg.Wait()
 }
@@ -70,31 +121,55 @@ func (swm *systemWideMetrics) serveGenericMetrics() error {
 // serveCPUInfo serves specific CPU metrics such as time spent servicing
 // different type of IRQs.
 func (swm *systemWideMetrics) serveCPUInfo() error {
+	if err := validateSinks(swm.sinks, core.TUI, core.JSONL); err != nil {
+		return err
+	}
+
 	eg, ctx := errgroup.WithContext(context.Background())
-	metricBus := make(chan *general.CPULoad, 1)
+	metricBus := utils.NewBroadcaster(&general.CPULoad{})
+
+	// start consuming metrics.
+	for _, sink := range swm.sinks {
+		bufferSize, policy := subscriptionFor(sink)
+		dataChannel := make(chan *general.CPULoad, bufferSize)
+		if _, err := metricBus.Subscribe(dataChannel, policy); err != nil {
+			return err
+		}
+
+		switch sink {
+		case core.TUI:
+			eg.Go(func() error {
+				return overallGraph.RenderCPUinfo(ctx, dataChannel, swm.refreshRate)
+			})
+		case core.JSONL:
+			eg.Go(func() error {
+				return jsonl.CPUInfo(ctx, dataChannel, swm.output)
+			})
+		}
+	}
 
 	// start producing metrics.
+	scraped := make(chan *general.CPULoad, 1)
 	eg.Go(func() error {
 		cpuLoad := general.NewCPULoad()
 		alteredRefreshRate := uint64(4 * swm.refreshRate / 5)
-		return general.GetCPULoad(ctx, cpuLoad, metricBus, alteredRefreshRate)
+		return general.GetCPULoad(ctx, cpuLoad, scraped, alteredRefreshRate)
+	})
+	eg.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case cpuLoad := <-scraped:
+				if err := metricBus.Publish(ctx, cpuLoad); err != nil {
+					return err
+				}
+			}
+		}
 	})
-
-	// start consuming metrics.
-	switch swm.sink {
-	case core.TUI:
-		eg.Go(func() error {
-			return overallGraph.RenderCPUinfo(ctx, metricBus, swm.refreshRate)
-		})
-	}
 
 	return eg.Wait()
 }
 
-// SetSink sets the Sink for the produced metrics.
-func (swm *systemWideMetrics) SetSink(sink core.Sink) {
-	swm.sink = sink
-}
-
 // ensure interface compliance.
 var _ MetricScraper = (*systemWideMetrics)(nil)
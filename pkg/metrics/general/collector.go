@@ -0,0 +1,243 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pesos/grofer/pkg/core"
+)
+
+// Collector collects one set of system wide metrics, such as
+// metrics related to memory or the network.
+type Collector interface {
+	// Name is the unique name of the Collector that it is
+	// enabled or disabled by.
+	Name() string
+	// FieldSet is the FieldSet of the AggregatedMetrics
+	// produced by the Collector, which determines the
+	// fields that are set.
+	FieldSet() string
+	// DefaultInterval is the interval the Collector is
+	// meant to be run at.
+	DefaultInterval() time.Duration
+	// Available reports whether the Collector can run on
+	// the system, for ex - a battery needs to be present
+	// to collect battery metrics.
+	Available() bool
+	// Collect collects a single sample of metrics.
+	Collect(ctx context.Context) (AggregatedMetrics, error)
+}
+
+// CollectorFunc is a Collector built from a function collecting the
+// metrics and a few static properties.
+type CollectorFunc struct {
+	// Probe reports whether the Collector can run on the
+	// system. The Collector is always available if nil.
+	Probe    func() bool
+	Func     func(ctx context.Context) (AggregatedMetrics, error)
+	name     string
+	fieldSet string
+	interval time.Duration
+}
+
+// NewCollectorFunc is a constructor for the CollectorFunc type.
+func NewCollectorFunc(name, fieldSet string, interval time.Duration, f func(context.Context) (AggregatedMetrics, error)) *CollectorFunc {
+	return &CollectorFunc{
+		Func:     f,
+		name:     name,
+		fieldSet: fieldSet,
+		interval: interval,
+	}
+}
+
+// WithProbe sets the availability probe of the Collector.
+func (cf *CollectorFunc) WithProbe(probe func() bool) *CollectorFunc {
+	cf.Probe = probe
+	return cf
+}
+
+// Name returns the name of the Collector.
+func (cf *CollectorFunc) Name() string { return cf.name }
+
+// FieldSet returns the FieldSet of the metrics produced by the Collector.
+func (cf *CollectorFunc) FieldSet() string { return cf.fieldSet }
+
+// DefaultInterval returns the interval the Collector is meant to be run at.
+func (cf *CollectorFunc) DefaultInterval() time.Duration { return cf.interval }
+
+// Available reports whether the Collector can run on the system.
+func (cf *CollectorFunc) Available() bool {
+	return cf.Probe == nil || cf.Probe()
+}
+
+// Collect collects a single sample of metrics and sets its FieldSet.
+func (cf *CollectorFunc) Collect(ctx context.Context) (AggregatedMetrics, error) {
+	data, err := cf.Func(ctx)
+	if err != nil {
+		return AggregatedMetrics{}, err
+	}
+	data.FieldSet = cf.fieldSet
+	return data, nil
+}
+
+// ensure interface compliance.
+var _ Collector = (*CollectorFunc)(nil)
+
+// Registry holds the Collectors that system wide metrics can be
+// collected with, in the order they were registered.
+type Registry struct {
+	collectors []Collector
+	mu         sync.RWMutex
+}
+
+// NewRegistry is a constructor for the Registry type.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a Collector to the Registry. It returns an error if a
+// Collector with the same name is already registered.
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.collectors {
+		if registered.Name() == c.Name() {
+			return fmt.Errorf("collector %s is already registered", c.Name())
+		}
+	}
+	r.collectors = append(r.collectors, c)
+	return nil
+}
+
+// Names returns the names of all registered Collectors.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// Select returns the registered Collectors that are available on the
+// system, keeping only the enabled ones and leaving out the disabled
+// ones. All Collectors are enabled if enabled is empty. It returns
+// core.ErrUnknownCollector if a name is not registered.
+func (r *Registry) Select(enabled, disabled []string) ([]Collector, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	isRegistered := func(name string) bool {
+		for _, c := range r.collectors {
+			if c.Name() == name {
+				return true
+			}
+		}
+		return false
+	}
+	contains := func(names []string, name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range append(append([]string{}, enabled...), disabled...) {
+		if !isRegistered(name) {
+			return nil, fmt.Errorf("%w: %s", core.ErrUnknownCollector, name)
+		}
+	}
+
+	selected := []Collector{}
+	for _, c := range r.collectors {
+		if len(enabled) > 0 && !contains(enabled, c.Name()) {
+			continue
+		}
+		if contains(disabled, c.Name()) || !c.Available() {
+			continue
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+// DefaultRegistry is the Registry holding the built-in Collectors.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a Collector to the DefaultRegistry.
+func Register(c Collector) error {
+	return DefaultRegistry.Register(c)
+}
+
+func init() {
+	// samplers of the built-in collectors that compute rates from
+	// consecutive samples instead of sleeping between two.
+	var (
+		cpus  cpuSampler
+		disks diskSampler
+		nics  netSampler
+	)
+
+	builtins := []Collector{
+		NewCollectorFunc("cpu", "CPU", 200*time.Millisecond, func(context.Context) (AggregatedMetrics, error) {
+			cpuRates, err := cpus.percent()
+			return AggregatedMetrics{CPUStats: cpuRates}, err
+		}),
+		NewCollectorFunc("mem", "MEM", 200*time.Millisecond, func(context.Context) (AggregatedMetrics, error) {
+			memStats, err := GetMemStats()
+			return AggregatedMetrics{MemStats: &memStats}, err
+		}),
+		NewCollectorFunc("disk", "DISK", 10*time.Second, func(context.Context) (AggregatedMetrics, error) {
+			diskStats, err := disks.stats()
+			return AggregatedMetrics{DiskStats: diskStats}, err
+		}),
+		NewCollectorFunc("net", "NET", 200*time.Millisecond, func(context.Context) (AggregatedMetrics, error) {
+			netStats, err := nics.stats()
+			return AggregatedMetrics{NetStats: netStats}, err
+		}),
+		NewCollectorFunc("temp", "TEMP", 2*time.Second, func(context.Context) (AggregatedMetrics, error) {
+			tempStats, err := GetTemperatureStats()
+			return AggregatedMetrics{TempStats: tempStats}, err
+		}),
+		NewCollectorFunc("info", "INFO", time.Minute, func(ctx context.Context) (AggregatedMetrics, error) {
+			info, err := GetHostInfo(ctx)
+			return AggregatedMetrics{HostInfo: &info}, err
+		}),
+		NewCollectorFunc("battery", "BATTERY", 30*time.Second, func(context.Context) (AggregatedMetrics, error) {
+			percent, err := GetBatteryPercent()
+			return AggregatedMetrics{BatteryPercent: percent}, err
+		}).WithProbe(func() bool {
+			_, err := GetBatteryPercent()
+			return err != core.ErrBatteryNotFound
+		}),
+	}
+
+	for _, c := range builtins {
+		if err := Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestRegistrySelect(t *testing.T) {
+	noop := func(context.Context) (AggregatedMetrics, error) { return AggregatedMetrics{}, nil }
+	unavailable := func() bool { return false }
+
+	r := NewRegistry()
+	utils.Raises(t, r.Register(NewCollectorFunc("a", "A", time.Second, noop)))
+	utils.Raises(t, r.Register(NewCollectorFunc("b", "B", time.Second, noop)))
+	utils.Raises(t, r.Register(NewCollectorFunc("c", "C", time.Second, noop).WithProbe(unavailable)))
+	utils.Assert(t, r.Register(NewCollectorFunc("a", "A", time.Second, noop)) != nil, "duplicate collector registered")
+
+	names := func(collectors []Collector) []string {
+		n := []string{}
+		for _, c := range collectors {
+			n = append(n, c.Name())
+		}
+		return n
+	}
+
+	tests := []struct {
+		enabled  []string
+		disabled []string
+		expected []string
+	}{
+		{nil, nil, []string{"a", "b"}},
+		{[]string{"b", "c"}, nil, []string{"b"}},
+		{nil, []string{"a"}, []string{"b"}},
+	}
+
+	for _, test := range tests {
+		collectors, err := r.Select(test.enabled, test.disabled)
+		utils.Raises(t, err)
+		utils.Equals(t, test.expected, names(collectors))
+	}
+
+	_, err := r.Select(nil, []string{"d"})
+	utils.Assert(t, errors.Is(err, core.ErrUnknownCollector), "expected ErrUnknownCollector, got %v", err)
+}
+
+func TestCollectorFuncSetsFieldSet(t *testing.T) {
+	c := NewCollectorFunc("a", "A", time.Second, func(context.Context) (AggregatedMetrics, error) {
+		return AggregatedMetrics{BatteryPercent: 50}, nil
+	})
+
+	data, err := c.Collect(context.Background())
+	utils.Raises(t, err)
+	utils.Equals(t, "A", data.FieldSet)
+	utils.Equals(t, 50.0, data.BatteryPercent)
+}
@@ -27,20 +27,94 @@ import (
 	"github.com/pesos/grofer/pkg/utils"
 )
 
+// cpuTimes holds the cumulative time in jiffies a CPU spent in each state,
+// in the order of the columns of a cpu line in /proc/stat: user, nice,
+// system, idle, iowait, irq, softirq, steal, guest and guest_nice.
+type cpuTimes [10]uint64
+
+// total returns the total time spent in all states. Guest time is left out
+// as it is already accounted for in user time.
+func (t cpuTimes) total() uint64 {
+	var sum uint64
+	for _, v := range t[:8] {
+		sum += v
+	}
+	return sum
+}
+
+// CoreLoad holds the share of time in percent a CPU, or all of them,
+// spent in each state.
+type CoreLoad struct {
+	Usr    float64 `json:"usr"`
+	Nice   float64 `json:"nice"`
+	Sys    float64 `json:"sys"`
+	Iowait float64 `json:"iowait"`
+	Soft   float64 `json:"soft"`
+	Steal  float64 `json:"steal"`
+	Guest  float64 `json:"guest"`
+	Gnice  float64 `json:"gnice"`
+	Idle   float64 `json:"idle"`
+	Irq    float64 `json:"irq"`
+}
+
+// Busy returns the share of time in percent the CPU was busy, that
+// is neither idle nor waiting on I/O.
+func (cl CoreLoad) Busy() float64 {
+	busy := 100 - cl.Idle - cl.Iowait
+	if busy < 0 {
+		return 0
+	}
+	return busy
+}
+
+// loadBetween returns the load of a CPU between the samples prev and cur.
+// It returns false if no time has elapsed between them.
+func loadBetween(prev, cur cpuTimes) (CoreLoad, bool) {
+	var delta cpuTimes
+	for i := range cur {
+		// counters may go backwards when a CPU goes offline.
+		if cur[i] > prev[i] {
+			delta[i] = cur[i] - prev[i]
+		}
+	}
+
+	total := float64(delta.total())
+	if total == 0 {
+		return CoreLoad{}, false
+	}
+
+	percent := func(v uint64) float64 {
+		return 100 * float64(v) / total
+	}
+	return CoreLoad{
+		Usr:    percent(delta[0]),
+		Nice:   percent(delta[1]),
+		Sys:    percent(delta[2]),
+		Idle:   percent(delta[3]),
+		Iowait: percent(delta[4]),
+		Irq:    percent(delta[5]),
+		Soft:   percent(delta[6]),
+		Steal:  percent(delta[7]),
+		Guest:  percent(delta[8]),
+		Gnice:  percent(delta[9]),
+	}, true
+}
+
 // CPULoad type contains info about load on CPU from various sources
-// as well as general stats about the CPU.
+// as well as general stats about the CPU. The load is in percent of
+// the time elapsed since the previous update, or since boot before
+// the first one.
 type CPULoad struct {
-	CPURates [][]string `json:"-"`
-	Usr      int        `json:"usr"`
-	Nice     int        `json:"nice"`
-	Sys      int        `json:"sys"`
-	Iowait   int        `json:"iowait"`
-	Soft     int        `json:"soft"`
-	Steal    int        `json:"steal"`
-	Guest    int        `json:"guest"`
-	Gnice    int        `json:"gnice"`
-	Idle     int        `json:"idle"`
-	Irq      int        `json:"irq"`
+	// CPURates holds the utilization of every CPU core in percent.
+	CPURates []float64 `json:"cpuRates"`
+	// PerCore holds the load of every CPU core, in the order
+	// of the cpuN lines of /proc/stat.
+	PerCore []CoreLoad `json:"perCore"`
+	// prevPerCore and prev are the samples the next update
+	// is computed against.
+	prevPerCore []cpuTimes
+	CoreLoad
+	prev cpuTimes
 }
 
 // NewCPULoad is a constructor for the CPULoad type.
@@ -48,75 +122,104 @@ func NewCPULoad() *CPULoad {
 	return &CPULoad{}
 }
 
-// ReadCPULoad reads /proc/stat and returns the total load on all CPU cores
+// parseCPUTimes parses a cpu line of /proc/stat such as
+// "cpu  4705 356 584 3699 23 23 0 0 0 0".
+func parseCPUTimes(line string) (cpuTimes, error) {
+	var times cpuTimes
+
+	// omit the 1st field as it only contains cpu/cpu<no>
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return times, fmt.Errorf("malformed cpu line in /proc/stat: %q", line)
+	}
+
+	// older kernels report fewer columns, which are left as 0.
+	for i, field := range fields[1:] {
+		if i >= len(times) {
+			break
+		}
+		val, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return times, err
+		}
+		times[i] = val
+	}
+	return times, nil
+}
+
+// setLoad sets the load from the time spent in each state since the
+// previous samples, and keeps the given samples as the new previous ones.
+func (c *CPULoad) setLoad(total cpuTimes, perCore []cpuTimes) {
+	if load, ok := loadBetween(c.prev, total); ok {
+		c.CoreLoad = load
+	}
+	c.prev = total
+
+	if len(c.prevPerCore) != len(perCore) {
+		// first sample or a CPU went on/offline.
+		c.prevPerCore = make([]cpuTimes, len(perCore))
+		c.PerCore = make([]CoreLoad, len(perCore))
+	}
+
+	// allocate new slices so that copies of a previous
+	// CPULoad are not changed by this update.
+	loads := make([]CoreLoad, len(perCore))
+	rates := make([]float64, len(perCore))
+	for i, times := range perCore {
+		loads[i] = c.PerCore[i]
+		if load, ok := loadBetween(c.prevPerCore[i], times); ok {
+			loads[i] = load
+		}
+		rates[i] = loads[i].Busy()
+	}
+	c.PerCore = loads
+	c.CPURates = rates
+	c.prevPerCore = append([]cpuTimes(nil), perCore...)
+}
+
+// readCPULoad reads /proc/stat and updates the total load on all CPU cores
+// as well as the load on every core.
 func (c *CPULoad) readCPULoad() error {
 	file, err := os.Open("/proc/stat")
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	reader := bufio.NewReader(file)
-	// Read first line containing load values
-	data, err := reader.ReadBytes(byte('\n'))
-	if err != nil {
-		return err
-	}
-	// Split the first line into an array and omit the 1st index value as it only contains cpu/cpu<no>
-	vals := strings.Fields(string(data))[1:]
-	var avg [10]float64
-	sum := 0
-	// Convert and store the load values into a floating point array
-	for i, x := range vals {
-		curr, err := strconv.Atoi(x)
+
+	var total cpuTimes
+	var perCore []cpuTimes
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "cpu") {
+			// the cpu lines come first.
+			break
+		}
+
+		times, err := parseCPUTimes(line)
 		if err != nil {
 			return err
 		}
-		avg[i] = float64(curr)
-		sum += curr
-	}
-	// Calculate average values
-	for i, x := range avg {
-		avg[i] = 100 * x / float64(sum)
-	}
-	// Store values in CPULoad struct
-	c.Usr = int(avg[0])
-	c.Nice = int(avg[1])
-	c.Sys = int(avg[2])
-	c.Idle = int(avg[3])
-	c.Iowait = int(avg[4])
-	c.Irq = int(avg[5])
-	c.Soft = int(avg[6])
-	c.Steal = int(avg[7])
-	c.Guest = int(avg[8])
-	c.Gnice = int(avg[9])
-
-	return err
-}
 
-// UpdateCPULoad updates fields of the type CPULoad
-func (c *CPULoad) UpdateCPULoad() error {
-	err := c.readCPULoad()
-	if err != nil {
-		return err
+		if strings.HasPrefix(line, "cpu ") {
+			total = times
+		} else {
+			perCore = append(perCore, times)
+		}
 	}
-	cpuRates, err := GetCPURates()
-	if err != nil {
+	if err := scanner.Err(); err != nil {
 		return err
 	}
 
-	rate := []string{}
-	cpus := []string{}
-	for i, cpuRate := range cpuRates {
-		cpus = append(cpus, "CPU "+strconv.Itoa(i))
-		rate = append(rate, fmt.Sprintf("%.2f%%", cpuRate))
-	}
-	rates := [][]string{cpus, rate}
-
-	c.CPURates = rates
-
+	c.setLoad(total, perCore)
 	return nil
 }
 
+// UpdateCPULoad updates fields of the type CPULoad
+func (c *CPULoad) UpdateCPULoad() error {
+	return c.readCPULoad()
+}
+
 // GetCPULoad updated the CPULoad struct and serves the data to the data channel.
 func GetCPULoad(ctx context.Context, cpuLoad *CPULoad, dataChannel chan *CPULoad, refreshRate uint64) error {
 	return utils.TickUntilDone(ctx, refreshRate, func() error {
@@ -125,10 +228,13 @@ func GetCPULoad(ctx context.Context, cpuLoad *CPULoad, dataChannel chan *CPULoad
 			return err
 		}
 
+		// send a copy so that consumers do not race with the next update.
+		snapshot := *cpuLoad
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case dataChannel <- cpuLoad:
+		case dataChannel <- &snapshot:
 			return nil
 		}
 	})
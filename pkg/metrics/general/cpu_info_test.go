@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestParseCPUTimes(t *testing.T) {
+	times, err := parseCPUTimes("cpu  4705 356 584 3699 23 23 0 0 0 0\n")
+	utils.Raises(t, err)
+	utils.Equals(t, cpuTimes{4705, 356, 584, 3699, 23, 23, 0, 0, 0, 0}, times)
+
+	// older kernels report fewer columns.
+	times, err = parseCPUTimes("cpu 1 2 3 4")
+	utils.Raises(t, err)
+	utils.Equals(t, cpuTimes{1, 2, 3, 4}, times)
+
+	_, err = parseCPUTimes("cpu")
+	utils.Assert(t, err != nil, "expected an error for a line without values")
+}
+
+func TestCPULoadIsPerInterval(t *testing.T) {
+	c := NewCPULoad()
+
+	// since boot, the CPU was mostly idle.
+	c.setLoad(cpuTimes{100, 0, 0, 900}, nil)
+	utils.Equals(t, 10.0, c.Usr)
+	utils.Equals(t, 90.0, c.Idle)
+
+	// in the last interval it spent half its time waiting on I/O.
+	c.setLoad(cpuTimes{110, 0, 10, 940, 40}, nil)
+	utils.Equals(t, 10.0, c.Usr)
+	utils.Equals(t, 10.0, c.Sys)
+	utils.Equals(t, 40.0, c.Idle)
+	utils.Equals(t, 40.0, c.Iowait)
+
+	// no time elapsed, the previous load is kept.
+	c.setLoad(cpuTimes{110, 0, 10, 940, 40}, nil)
+	utils.Equals(t, 40.0, c.Iowait)
+}
+
+func TestCPULoadPerCore(t *testing.T) {
+	c := NewCPULoad()
+
+	c.setLoad(cpuTimes{}, []cpuTimes{{10, 0, 0, 90}, {0, 0, 0, 100}})
+	snapshot := *c
+
+	// the second core spends its time servicing softirqs.
+	c.setLoad(cpuTimes{}, []cpuTimes{{20, 0, 0, 180}, {0, 0, 0, 150, 0, 0, 50}})
+	utils.Equals(t, 2, len(c.PerCore))
+	utils.Equals(t, 10.0, c.PerCore[0].Usr)
+	utils.Equals(t, 50.0, c.PerCore[1].Soft)
+	utils.Equals(t, []float64{10, 50}, c.CPURates)
+
+	// previous copies are left untouched.
+	utils.Equals(t, 0.0, snapshot.PerCore[1].Soft)
+}
@@ -18,63 +18,90 @@ package general
 
 import (
 	"context"
-	"sync"
+	"time"
 
-	"github.com/pesos/grofer/pkg/core"
-	"github.com/pesos/grofer/pkg/utils"
+	"golang.org/x/sync/errgroup"
 )
 
-// AggregatedMetrics represents global metrics to be consumed.
+// AggregatedMetrics represents global metrics to be consumed. Only the
+// fields of the FieldSet it was produced for are set. Values are kept in
+// base units and formatting them is left to the sinks.
 type AggregatedMetrics struct {
-	NetStats       map[string][]float64
-	FieldSet       string
-	CPUStats       []float64
-	MemStats       []float64
-	DiskStats      [][]string
-	TempStats      [][]string
-	HostInfo       [][]string
-	BatteryPercent int
+	Timestamp time.Time           `json:"timestamp"`
+	NetStats  map[string]NetStats `json:"net,omitempty"`
+	MemStats  *MemoryStats        `json:"mem,omitempty"`
+	HostInfo  *HostInfo           `json:"info,omitempty"`
+	FieldSet  string              `json:"fieldSet"`
+	// CPUStats holds the utilization of every CPU core in percent.
+	CPUStats  []float64          `json:"cpu,omitempty"`
+	DiskStats []DiskStats        `json:"disk,omitempty"`
+	TempStats []TemperatureStats `json:"temp,omitempty"`
+	// BatteryPercent is the remaining battery charge in percent.
+	BatteryPercent float64 `json:"battery,omitempty"`
 }
 
-type serveFunc func(context.Context, chan AggregatedMetrics) error
-
-// GlobalStats gets stats about the mem and the CPUs and prints it.
-func GlobalStats(ctx context.Context, dataChannel chan AggregatedMetrics, refreshRate uint64) error {
-	serveFuncs := []serveFunc{
-		ServeCPURates,
-		ServeMemRates,
-		ServeDiskRates,
-		ServeNetRates,
-		ServeTemperatureRates,
-		ServeInfo,
-		ServeBattery,
-	}
+// MemoryStats holds physical memory usage in bytes.
+type MemoryStats struct {
+	Total     uint64 `json:"totalBytes"`
+	Used      uint64 `json:"usedBytes"`
+	Available uint64 `json:"availableBytes"`
+	Free      uint64 `json:"freeBytes"`
+	Cached    uint64 `json:"cachedBytes"`
+}
 
-	return utils.TickUntilDone(ctx, refreshRate, func() error {
-		var wg sync.WaitGroup
+// DiskStats holds usage of a mounted filesystem in bytes, and the rates at
+// which its device is read from and written to.
+type DiskStats struct {
+	Device              string  `json:"device"`
+	Mountpoint          string  `json:"mountpoint"`
+	Fstype              string  `json:"fstype"`
+	Total               uint64  `json:"totalBytes"`
+	Used                uint64  `json:"usedBytes"`
+	Free                uint64  `json:"freeBytes"`
+	UsedPercent         float64 `json:"usedPercent"`
+	ReadBytesPerSecond  float64 `json:"readBytesPerSecond"`
+	WriteBytesPerSecond float64 `json:"writeBytesPerSecond"`
+}
 
-		errCh := make(chan error, len(serveFuncs))
+// NetStats holds the bytes transferred on a network interface since boot,
+// and the rates at which they were recently transferred.
+type NetStats struct {
+	BytesSent          uint64  `json:"bytesSent"`
+	BytesRecv          uint64  `json:"bytesRecv"`
+	SentBytesPerSecond float64 `json:"sentBytesPerSecond"`
+	RecvBytesPerSecond float64 `json:"recvBytesPerSecond"`
+}
 
-		for _, sf := range serveFuncs {
-			wg.Add(1)
-			go func(sf serveFunc, dc chan AggregatedMetrics) {
-				defer wg.Done()
-				errCh <- sf(ctx, dc)
-			}(sf, dataChannel)
-		}
+// TemperatureStats holds the reading of a temperature sensor.
+type TemperatureStats struct {
+	Sensor  string  `json:"sensor"`
+	Celsius float64 `json:"celsius"`
+}
 
-		wg.Wait()
-		close(errCh)
-		for err := range errCh {
-			if err != nil {
-				if err == core.ErrBatteryNotFound {
-					serveFuncs = serveFuncs[:len(serveFuncs)-1]
-				} else {
-					return err
-				}
-			}
-		}
+// HostInfo holds information about the system such as OS info, uptime,
+// boot time, etc.
+type HostInfo struct {
+	BootTime        time.Time `json:"bootTime"`
+	Hostname        string    `json:"hostname"`
+	OS              string    `json:"os"`
+	Platform        string    `json:"platform"`
+	PlatformVersion string    `json:"platformVersion"`
+	KernelVersion   string    `json:"kernelVersion"`
+	KernelArch      string    `json:"kernelArch"`
+	UptimeSeconds   uint64    `json:"uptimeSeconds"`
+	Procs           uint64    `json:"procs"`
+}
 
-		return nil
-	})
+// GlobalStats runs every collector at its own interval and serves the
+// metrics they produce to the data channel, so that slow collectors do
+// not hold back fast ones.
+func GlobalStats(ctx context.Context, collectors []ScheduledCollector, dataChannel chan AggregatedMetrics) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, c := range collectors {
+		c := c
+		eg.Go(func() error {
+			return c.run(ctx, dataChannel)
+		})
+	}
+	return eg.Wait()
 }
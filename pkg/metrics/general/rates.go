@@ -0,0 +1,218 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+)
+
+// cpuSampler computes the utilization of every CPU core from the
+// difference between consecutive samples of CPU times, so that it
+// never has to sleep between two samples itself.
+type cpuSampler struct {
+	prev []cpu.TimesStat
+	mu   sync.Mutex
+}
+
+// percent returns the utilization of every CPU core in percent since
+// the previous call, or since boot on the first call.
+func (s *cpuSampler) percent() ([]float64, error) {
+	times, err := cpu.Times(true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := s.prev
+	if len(prev) != len(times) {
+		// first sample or a CPU went on/offline.
+		prev = make([]cpu.TimesStat, len(times))
+	}
+	s.prev = times
+
+	rates := make([]float64, len(times))
+	for i := range times {
+		rates[i] = busyPercent(prev[i], times[i])
+	}
+	return rates, nil
+}
+
+// busyPercent returns the percentage of time a CPU was busy between
+// the samples t1 and t2.
+func busyPercent(t1, t2 cpu.TimesStat) float64 {
+	busy := func(t cpu.TimesStat) (float64, float64) {
+		b := t.User + t.System + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+		return b + t.Idle, b
+	}
+	t1All, t1Busy := busy(t1)
+	t2All, t2Busy := busy(t2)
+
+	if t2Busy <= t1Busy {
+		return 0
+	}
+	if t2All <= t1All {
+		return 100
+	}
+	percent := (t2Busy - t1Busy) / (t2All - t1All) * 100
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// counterSampler turns monotonically increasing counters keyed by
+// name into rates per second from the difference between consecutive
+// samples.
+type counterSampler struct {
+	prev     map[string]uint64
+	prevTime time.Time
+	mu       sync.Mutex
+}
+
+// rates returns the rate per second of every counter since the previous
+// call. Counters seen for the first time, or that were reset, have a
+// rate of 0.
+func (s *counterSampler) rates(counters map[string]uint64) map[string]float64 {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := now.Sub(s.prevTime).Seconds()
+	rates := make(map[string]float64, len(counters))
+	for name, val := range counters {
+		prev, ok := s.prev[name]
+		if ok && val >= prev && elapsed > 0 {
+			rates[name] = float64(val-prev) / elapsed
+		} else {
+			rates[name] = 0
+		}
+	}
+
+	s.prev = counters
+	s.prevTime = now
+	return rates
+}
+
+// netSampler computes the transfer rates of network interfaces.
+type netSampler struct {
+	counters counterSampler
+}
+
+// stats returns the bytes transferred on every network interface along
+// with the transfer rates since the previous call.
+func (s *netSampler) stats() (map[string]NetStats, error) {
+	netStats, err := GetNetStats()
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]uint64, 2*len(netStats))
+	for nic, stat := range netStats {
+		counters[nic+"/sent"] = stat.BytesSent
+		counters[nic+"/recv"] = stat.BytesRecv
+	}
+	rates := s.counters.rates(counters)
+
+	for nic, stat := range netStats {
+		stat.SentBytesPerSecond = rates[nic+"/sent"]
+		stat.RecvBytesPerSecond = rates[nic+"/recv"]
+		netStats[nic] = stat
+	}
+	return netStats, nil
+}
+
+// diskSampler computes the read and write rates of the devices backing
+// mounted filesystems.
+type diskSampler struct {
+	counters counterSampler
+}
+
+// stats returns the usage of mounted filesystems along with the read and
+// write rates of their devices since the previous call.
+func (s *diskSampler) stats() ([]DiskStats, error) {
+	diskStats, err := GetDiskStats()
+	if err != nil {
+		return nil, err
+	}
+
+	ioCounters, err := disk.IOCounters()
+	if err != nil {
+		// not every system exposes I/O counters, usage is still useful.
+		return diskStats, nil
+	}
+
+	counters := make(map[string]uint64, 2*len(ioCounters))
+	for device, stat := range ioCounters {
+		counters[device+"/read"] = stat.ReadBytes
+		counters[device+"/write"] = stat.WriteBytes
+	}
+	rates := s.counters.rates(counters)
+
+	for i, d := range diskStats {
+		device := filepath.Base(d.Device)
+		diskStats[i].ReadBytesPerSecond = rates[device+"/read"]
+		diskStats[i].WriteBytesPerSecond = rates[device+"/write"]
+	}
+	return diskStats, nil
+}
+
+// RateSampler computes the utilization of every CPU core and the transfer
+// rates of disks and network interfaces between its own successive
+// samples, for callers that sample on their own schedule.
+type RateSampler struct {
+	cpus  cpuSampler
+	disks diskSampler
+	nics  netSampler
+}
+
+// NewRateSampler is a constructor for the RateSampler type.
+func NewRateSampler() *RateSampler {
+	return &RateSampler{}
+}
+
+// CPURates returns the utilization of every CPU core in percent since the
+// previous call, or since boot on the first call.
+func (s *RateSampler) CPURates() ([]float64, error) {
+	rates, err := s.cpus.percent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPU times: %w", err)
+	}
+	return rates, nil
+}
+
+// DiskStats returns the usage of mounted filesystems along with the read
+// and write rates of their devices since the previous call, which are 0
+// on the first call.
+func (s *RateSampler) DiskStats() ([]DiskStats, error) {
+	return s.disks.stats()
+}
+
+// NetStats returns the bytes transferred on every network interface along
+// with the transfer rates since the previous call, which are 0 on the
+// first call.
+func (s *RateSampler) NetStats() (map[string]NetStats, error) {
+	return s.nics.stats()
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/utils"
+	"github.com/shirou/gopsutil/cpu"
+)
+
+func TestBusyPercent(t *testing.T) {
+	tests := []struct {
+		t1, t2      cpu.TimesStat
+		expectedVal float64
+	}{
+		{cpu.TimesStat{}, cpu.TimesStat{User: 1, Idle: 3}, 25},
+		{cpu.TimesStat{User: 10, Idle: 10}, cpu.TimesStat{User: 15, System: 5, Idle: 10}, 100},
+		{cpu.TimesStat{User: 10, Idle: 10}, cpu.TimesStat{User: 10, Idle: 20}, 0},
+	}
+
+	for _, test := range tests {
+		utils.Equals(t, test.expectedVal, busyPercent(test.t1, test.t2))
+	}
+}
+
+func TestCounterSamplerRates(t *testing.T) {
+	var s counterSampler
+
+	rates := s.rates(map[string]uint64{"eth0": 1000})
+	utils.Equals(t, 0.0, rates["eth0"])
+
+	// pretend the previous sample was taken two seconds ago.
+	s.prevTime = s.prevTime.Add(-2 * time.Second)
+	rates = s.rates(map[string]uint64{"eth0": 3000, "eth1": 10})
+	utils.Assert(t, rates["eth0"] > 990 && rates["eth0"] <= 1000, "unexpected rate %f", rates["eth0"])
+	utils.Equals(t, 0.0, rates["eth1"])
+
+	// counters that were reset have no rate.
+	rates = s.rates(map[string]uint64{"eth0": 5})
+	utils.Equals(t, 0.0, rates["eth0"])
+}
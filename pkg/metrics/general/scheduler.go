@@ -0,0 +1,108 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"context"
+	"time"
+)
+
+// maxBackoff is the longest a failing collector waits before it
+// is retried, unless its interval is longer.
+const maxBackoff = time.Minute
+
+// ScheduledCollector is a Collector that is run at its own interval.
+type ScheduledCollector struct {
+	Collector
+	Interval time.Duration
+}
+
+// Schedule schedules every collector at the interval in intervals keyed by
+// its name. Collectors without one are scheduled at their default interval,
+// or at refreshRate if that is longer.
+func Schedule(collectors []Collector, refreshRate time.Duration, intervals map[string]time.Duration) []ScheduledCollector {
+	scheduled := make([]ScheduledCollector, 0, len(collectors))
+	for _, c := range collectors {
+		interval, ok := intervals[c.Name()]
+		if !ok || interval <= 0 {
+			interval = c.DefaultInterval()
+			if refreshRate > interval {
+				interval = refreshRate
+			}
+		}
+		scheduled = append(scheduled, ScheduledCollector{
+			Collector: c,
+			Interval:  interval,
+		})
+	}
+	return scheduled
+}
+
+// backoff returns how long a collector that failed the given number of
+// consecutive times waits before it is retried.
+func (sc ScheduledCollector) backoff(failures int) time.Duration {
+	limit := maxBackoff
+	if sc.Interval > limit {
+		limit = sc.Interval
+	}
+
+	wait := sc.Interval
+	for i := 0; i < failures && wait < limit; i++ {
+		wait *= 2
+	}
+	if wait > limit {
+		wait = limit
+	}
+	return wait
+}
+
+// run runs the collector at its interval and serves the metrics it produces
+// to the data channel until the context is cancelled. A failing collector
+// backs off and is retried rather than returning an error.
+func (sc ScheduledCollector) run(ctx context.Context, dataChannel chan AggregatedMetrics) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		start := time.Now()
+		data, err := sc.Collect(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failures++
+			timer.Reset(sc.backoff(failures))
+			continue
+		}
+		failures = 0
+
+		if err := serve(ctx, dataChannel, data); err != nil {
+			return err
+		}
+
+		// account for the time spent collecting so that
+		// the collector does not drift from its interval.
+		timer.Reset(sc.Interval - time.Since(start))
+	}
+}
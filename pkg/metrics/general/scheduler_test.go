@@ -0,0 +1,79 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package general
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestSchedule(t *testing.T) {
+	noop := func(context.Context) (AggregatedMetrics, error) { return AggregatedMetrics{}, nil }
+	collectors := []Collector{
+		NewCollectorFunc("fast", "FAST", 500*time.Millisecond, noop),
+		NewCollectorFunc("slow", "SLOW", 10*time.Second, noop),
+		NewCollectorFunc("custom", "CUSTOM", time.Second, noop),
+	}
+
+	scheduled := Schedule(collectors, time.Second, map[string]time.Duration{"custom": 200 * time.Millisecond})
+
+	utils.Equals(t, time.Second, scheduled[0].Interval)
+	utils.Equals(t, 10*time.Second, scheduled[1].Interval)
+	utils.Equals(t, 200*time.Millisecond, scheduled[2].Interval)
+}
+
+func TestScheduledCollectorBackoff(t *testing.T) {
+	sc := ScheduledCollector{Interval: time.Second}
+
+	utils.Equals(t, 2*time.Second, sc.backoff(1))
+	utils.Equals(t, 8*time.Second, sc.backoff(3))
+	utils.Equals(t, maxBackoff, sc.backoff(10))
+}
+
+func TestGlobalStatsRetriesFailingCollectors(t *testing.T) {
+	var calls int32
+	flaky := NewCollectorFunc("flaky", "FLAKY", 0, func(context.Context) (AggregatedMetrics, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return AggregatedMetrics{}, errors.New("transient failure")
+		}
+		return AggregatedMetrics{}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dataChannel := make(chan AggregatedMetrics)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- GlobalStats(ctx, []ScheduledCollector{{Collector: flaky, Interval: time.Millisecond}}, dataChannel)
+	}()
+
+	select {
+	case data := <-dataChannel:
+		utils.Equals(t, "FLAKY", data.FieldSet)
+	case err := <-errCh:
+		t.Fatalf("GlobalStats returned before retrying: %v", err)
+	}
+
+	cancel()
+	utils.Equals(t, context.Canceled, <-errCh)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limit is a resource limit of a process.
+type Limit struct {
+	Name  string
+	Soft  string
+	Hard  string
+	Units string
+}
+
+// Cgroup is the cgroup of a process in one cgroup hierarchy.
+type Cgroup struct {
+	Hierarchy   string // 0 for the unified hierarchy of cgroup v2.
+	Controllers string
+	Path        string
+}
+
+// Namespace is a namespace a process is a member of.
+type Namespace struct {
+	Type string
+	ID   string // inode number of the namespace.
+	// Shared is whether grofer is a member of the namespace too.
+	Shared bool
+}
+
+// Scheduling holds the scheduling settings of a process.
+type Scheduling struct {
+	Policy      string
+	IOPriority  string // empty if the I/O priority cannot be read.
+	CPUAffinity []int  // empty if the affinity cannot be read.
+	RTPriority  int32
+	Nice        int32
+	LastCPU     int32 // -1 if not reported.
+}
+
+// Details holds the environment, limits, cgroups, namespaces, scheduling
+// and arguments of a process. Reading some of them is reserved to the
+// owner of the process and root, so each section keeps the error it was
+// read with, nil if it was read.
+type Details struct {
+	Argv          []string
+	Environ       []string // KEY=value, ordered by key.
+	Limits        []Limit
+	Cgroups       []Cgroup
+	Namespaces    []Namespace
+	ArgvErr       error
+	EnvironErr    error
+	LimitsErr     error
+	CgroupsErr    error
+	NamespacesErr error
+	SchedulingErr error
+	Scheduling    Scheduling
+}
+
+// schedPolicies maps the scheduling policies in /proc/<pid>/stat to their
+// names in sched(7).
+var schedPolicies = map[int32]string{
+	0: "SCHED_OTHER",
+	1: "SCHED_FIFO",
+	2: "SCHED_RR",
+	3: "SCHED_BATCH",
+	5: "SCHED_IDLE",
+	6: "SCHED_DEADLINE",
+}
+
+// readDetails reads the details of a process from /proc/<pid>. The I/O
+// priority and CPU affinity are not kept in /proc and are left empty.
+func readDetails(procfs string, pid int32) Details {
+	dir := filepath.Join(procfs, strconv.Itoa(int(pid)))
+
+	var d Details
+	d.Argv, d.ArgvErr = readNulSeparated(filepath.Join(dir, "cmdline"))
+	d.Environ, d.EnvironErr = readNulSeparated(filepath.Join(dir, "environ"))
+	sort.Strings(d.Environ)
+	d.Limits, d.LimitsErr = readLimits(filepath.Join(dir, "limits"))
+	d.Cgroups, d.CgroupsErr = readCgroups(filepath.Join(dir, "cgroup"))
+	d.Namespaces, d.NamespacesErr = readNamespaces(filepath.Join(dir, "ns"), filepath.Join(procfs, "self", "ns"))
+	d.Scheduling, d.SchedulingErr = readScheduling(filepath.Join(dir, "stat"))
+	return d
+}
+
+// readNulSeparated returns the strings of a file of strings terminated by
+// NUL bytes, such as /proc/<pid>/cmdline.
+func readNulSeparated(path string) ([]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents = bytes.TrimRight(contents, "\x00")
+	if len(contents) == 0 {
+		return []string{}, nil
+	}
+	return strings.Split(string(contents), "\x00"), nil
+}
+
+// readLimits returns the resource limits from /proc/<pid>/limits. Its
+// columns are aligned with padding, and limit names contain spaces, so the
+// values are cut at the offsets of the columns in the header.
+func readLimits(path string) ([]Limit, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(contents), "\n"), "\n")
+	soft := strings.Index(lines[0], "Soft Limit")
+	hard := strings.Index(lines[0], "Hard Limit")
+	units := strings.Index(lines[0], "Units")
+	if soft < 0 || hard < soft || units < hard {
+		return nil, fmt.Errorf("malformed limits header: %q", lines[0])
+	}
+
+	cut := func(line string, from, to int) string {
+		if from >= len(line) {
+			return ""
+		}
+		if to > len(line) || to < 0 {
+			to = len(line)
+		}
+		return strings.TrimSpace(line[from:to])
+	}
+
+	limits := make([]Limit, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		limits = append(limits, Limit{
+			Name:  cut(line, 0, soft),
+			Soft:  cut(line, soft, hard),
+			Hard:  cut(line, hard, units),
+			Units: cut(line, units, -1),
+		})
+	}
+	return limits, nil
+}
+
+// readCgroups returns the cgroups of a process in every hierarchy from
+// /proc/<pid>/cgroup.
+func readCgroups(path string) ([]Cgroup, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cgroups := []Cgroup{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		// each line is hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		cgroups = append(cgroups, Cgroup{
+			Hierarchy:   fields[0],
+			Controllers: fields[1],
+			Path:        fields[2],
+		})
+	}
+	return cgroups, nil
+}
+
+// readNamespaces returns the namespaces of a process, ordered by type, from
+// the links of /proc/<pid>/ns, which read like "net:[4026531992]". They
+// are compared with the namespaces of grofer in selfDir.
+func readNamespaces(dir, selfDir string) ([]Namespace, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]Namespace, 0, len(entries))
+	for _, entry := range entries {
+		link, err := os.Readlink(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		ns := Namespace{Type: entry.Name(), ID: link}
+		if start, end := strings.IndexByte(link, '['), strings.LastIndexByte(link, ']'); start >= 0 && end > start {
+			ns.ID = link[start+1 : end]
+		}
+		if self, err := os.Readlink(filepath.Join(selfDir, entry.Name())); err == nil {
+			ns.Shared = self == link
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, nil
+}
+
+// readScheduling returns the scheduling policy, priorities and last CPU of
+// a process from /proc/<pid>/stat.
+func readScheduling(path string) (Scheduling, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Scheduling{}, err
+	}
+	stat, err := parseStat(contents)
+	if err != nil {
+		return Scheduling{}, err
+	}
+
+	policy, ok := schedPolicies[stat.policy]
+	if !ok {
+		policy = fmt.Sprintf("unknown (%d)", stat.policy)
+	}
+	return Scheduling{
+		Policy:     policy,
+		RTPriority: stat.rtPriority,
+		Nice:       stat.nice,
+		LastCPU:    stat.processor,
+	}, nil
+}
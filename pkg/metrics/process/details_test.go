@@ -0,0 +1,81 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestReadDetails(t *testing.T) {
+	procfs := t.TempDir()
+	dir := filepath.Join(procfs, "42")
+	utils.Raises(t, os.MkdirAll(filepath.Join(dir, "ns"), 0755))
+	utils.Raises(t, os.MkdirAll(filepath.Join(procfs, "self", "ns"), 0755))
+
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte("nginx\x00-g\x00daemon off;\x00"), 0644))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "environ"), []byte("PATH=/usr/bin\x00HOME=/root\x00"), 0644))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "limits"), []byte(
+		"Limit                     Soft Limit           Hard Limit           Units     \n"+
+			"Max cpu time              unlimited            unlimited            seconds   \n"+
+			"Max open files            1024                 524288               files     \n"+
+			"Max realtime timeout      unlimited            unlimited            us        \n"), 0644))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "cgroup"), []byte("4:memory:/docker/abc\n0::/system.slice/nginx.service\n"), 0644))
+	utils.Raises(t, os.Symlink("net:[4026532001]", filepath.Join(dir, "ns", "net")))
+	utils.Raises(t, os.Symlink("pid:[4026531836]", filepath.Join(dir, "ns", "pid")))
+	utils.Raises(t, os.Symlink("pid:[4026531836]", filepath.Join(procfs, "self", "ns", "pid")))
+	utils.Raises(t, os.Symlink("net:[4026531992]", filepath.Join(procfs, "self", "ns", "net")))
+
+	// SCHED_FIFO with a realtime priority of 50, last run on CPU 3.
+	fields := "42 (nginx) S 1 42 42 0 -1 0 0 0 0 0 1 1 0 0 -51 0 1 0 500 1024 7 0 0 0 0 0 0 0 0 0 0 0 0 0 0 3 50 1\n"
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(fields), 0644))
+
+	d := readDetails(procfs, 42)
+	utils.Equals(t, []string{"nginx", "-g", "daemon off;"}, d.Argv)
+	utils.Equals(t, []string{"HOME=/root", "PATH=/usr/bin"}, d.Environ)
+
+	utils.Raises(t, d.LimitsErr)
+	utils.Equals(t, 3, len(d.Limits))
+	utils.Equals(t, Limit{Name: "Max open files", Soft: "1024", Hard: "524288", Units: "files"}, d.Limits[1])
+	utils.Equals(t, "Max realtime timeout", d.Limits[2].Name)
+
+	utils.Equals(t, []Cgroup{
+		{Hierarchy: "4", Controllers: "memory", Path: "/docker/abc"},
+		{Hierarchy: "0", Controllers: "", Path: "/system.slice/nginx.service"},
+	}, d.Cgroups)
+
+	utils.Equals(t, []Namespace{
+		{Type: "net", ID: "4026532001", Shared: false},
+		{Type: "pid", ID: "4026531836", Shared: true},
+	}, d.Namespaces)
+
+	utils.Raises(t, d.SchedulingErr)
+	utils.Equals(t, Scheduling{Policy: "SCHED_FIFO", RTPriority: 50, LastCPU: 3}, d.Scheduling)
+}
+
+func TestReadDetailsKeepsErrors(t *testing.T) {
+	d := readDetails(t.TempDir(), 42)
+	utils.Assert(t, errors.Is(d.EnvironErr, os.ErrNotExist), "expected a missing environ, got %v", d.EnvironErr)
+	utils.Assert(t, d.LimitsErr != nil, "expected an error reading the limits")
+	utils.Assert(t, d.NamespacesErr != nil, "expected an error reading the namespaces")
+	utils.Equals(t, 0, len(d.Environ))
+}
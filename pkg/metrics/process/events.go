@@ -0,0 +1,227 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// EventKind is the kind of change in the list of running processes.
+type EventKind int
+
+const (
+	// ProcessStarted is recorded for a process that was not running in the
+	// previous snapshot.
+	ProcessStarted EventKind = iota
+	// ProcessExited is recorded for a process that is no longer running.
+	ProcessExited
+)
+
+// String returns the name of the kind of event.
+func (k EventKind) String() string {
+	switch k {
+	case ProcessStarted:
+		return "start"
+	case ProcessExited:
+		return "exit"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a process starting or exiting, as observed between two
+// snapshots. Processes that start and exit between two snapshots are not
+// observed.
+type Event struct {
+	Time    time.Time // when the event was observed.
+	Process Snapshot  // first snapshot of a started process, last of an exited one.
+	// Lifetime is the time from the start of the process to the event.
+	Lifetime time.Duration
+	// PeakRSS is the highest RSS reported or observed up to the event.
+	PeakRSS uint64
+	// ExitStatus describes how a process exited, empty if it was not seen
+	// as a zombie before being reaped.
+	ExitStatus string
+	Kind       EventKind
+}
+
+// EventLog records the processes that start and exit between successive
+// snapshots. It keeps the latest events up to its size.
+type EventLog struct {
+	filter *Filter
+	size   int
+	// seen holds the last snapshot of every running process, with the
+	// peak RSS raised to the highest RSS observed and the last known
+	// command line.
+	seen   map[Key]Snapshot
+	primed bool
+	// events is a ring of up to size events, whose oldest event is at
+	// head once it is full.
+	events []Event
+	head   int
+	// total is the number of events recorded since the log was created.
+	total int
+	mu    sync.Mutex
+}
+
+// NewEventLog returns an EventLog keeping the latest size events of the
+// processes selected by the filter.
+func NewEventLog(size int, filter *Filter) *EventLog {
+	return &EventLog{
+		filter: filter,
+		size:   size,
+		seen:   make(map[Key]Snapshot),
+	}
+}
+
+// Observe records the processes started and exited since the previous
+// snapshot. The processes running in the first snapshot are not recorded
+// as started.
+func (l *EventLog) Observe(procs []Snapshot, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cur := make(map[Key]Snapshot, len(procs))
+	started := []Snapshot{}
+	for _, p := range procs {
+		// a PID reused between two snapshots shows up as an exit
+		// followed by a start.
+		key := p.Key()
+		prev, ok := l.seen[key]
+		if !ok && l.primed && l.filter.Match(p) {
+			started = append(started, p)
+		}
+		// zombies no longer have a command line.
+		if p.Cmdline == "" {
+			p.Cmdline = prev.Cmdline
+		}
+		if prev.PeakRSS > p.PeakRSS {
+			p.PeakRSS = prev.PeakRSS
+		}
+		if p.RSS > p.PeakRSS {
+			p.PeakRSS = p.RSS
+		}
+		cur[key] = p
+	}
+
+	exited := []Snapshot{}
+	for key, p := range l.seen {
+		if _, ok := cur[key]; !ok && l.filter.Match(p) {
+			exited = append(exited, p)
+		}
+	}
+	sort.Slice(exited, func(i, j int) bool {
+		return exited[i].PID < exited[j].PID
+	})
+
+	// exits come first, as a PID may be reused by a process started since.
+	for _, p := range exited {
+		l.record(Event{
+			Time:       now,
+			Kind:       ProcessExited,
+			Process:    p,
+			Lifetime:   lifetime(p, now),
+			PeakRSS:    p.PeakRSS,
+			ExitStatus: exitStatus(p),
+		})
+	}
+	for _, p := range started {
+		l.record(Event{
+			Time:     now,
+			Kind:     ProcessStarted,
+			Process:  p,
+			Lifetime: lifetime(p, now),
+			PeakRSS:  p.PeakRSS,
+		})
+	}
+
+	l.seen = cur
+	l.primed = true
+}
+
+// record appends an event, replacing the oldest one beyond the size.
+func (l *EventLog) record(e Event) {
+	l.total++
+	switch {
+	case l.size <= 0:
+	case len(l.events) < l.size:
+		l.events = append(l.events, e)
+	default:
+		l.events[l.head] = e
+		l.head = (l.head + 1) % l.size
+	}
+}
+
+// Events returns the events kept by the log, oldest first.
+func (l *EventLog) Events() []Event {
+	events, _ := l.Since(0)
+	return events
+}
+
+// Since returns the events kept by the log after the first n recorded, and
+// the number of events recorded so far to pass to the next call.
+func (l *EventLog) Since(n int) ([]Event, int) {
+	if l == nil {
+		return nil, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// index of the oldest event kept among all those recorded.
+	first := l.total - len(l.events)
+	if n < first {
+		n = first
+	}
+	if n > l.total {
+		n = l.total
+	}
+	events := make([]Event, 0, l.total-n)
+	for i := n - first; i < len(l.events); i++ {
+		events = append(events, l.events[(l.head+i)%len(l.events)])
+	}
+	return events, l.total
+}
+
+// lifetime returns the time from the start of a process to now.
+func lifetime(p Snapshot, now time.Time) time.Duration {
+	created := time.Unix(0, p.CreateTime*int64(time.Millisecond))
+	if now.Before(created) {
+		return 0
+	}
+	return now.Sub(created)
+}
+
+// exitStatus describes the wait status of a process last seen as a zombie.
+func exitStatus(p Snapshot) string {
+	if p.Status != "Z" {
+		return ""
+	}
+
+	ws := syscall.WaitStatus(p.ExitCode)
+	switch {
+	case ws.Exited():
+		return fmt.Sprintf("exited %d", ws.ExitStatus())
+	case ws.Signaled():
+		return fmt.Sprintf("signal %d (%v)", int(ws.Signal()), ws.Signal())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestEventLogObserve(t *testing.T) {
+	start := time.Unix(1000, 0)
+	sh := Snapshot{PID: 10, Name: "sh", CreateTime: 990000, RSS: 4096}
+	build := Snapshot{PID: 11, PPID: 10, Name: "make", Cmdline: "make all", CreateTime: 1000500, RSS: 1 << 20}
+
+	l := NewEventLog(10, nil)
+
+	// the processes running at first are not recorded as started.
+	l.Observe([]Snapshot{sh}, start)
+	utils.Equals(t, 0, len(l.Events()))
+
+	l.Observe([]Snapshot{sh, build}, start.Add(time.Second))
+	events := l.Events()
+	utils.Equals(t, 1, len(events))
+	utils.Equals(t, ProcessStarted, events[0].Kind)
+	utils.Equals(t, int32(11), events[0].Process.PID)
+	utils.Equals(t, 500*time.Millisecond, events[0].Lifetime)
+
+	// the peak RSS is the highest RSS observed.
+	build.RSS = 8 << 20
+	l.Observe([]Snapshot{sh, build}, start.Add(2*time.Second))
+	build.RSS = 2 << 20
+	build.Status = "Z"
+	build.Cmdline = ""
+	build.ExitCode = 2 << 8
+	l.Observe([]Snapshot{sh, build}, start.Add(3*time.Second))
+
+	// sh exits without being seen as a zombie, and its PID is reused.
+	reused := Snapshot{PID: 10, Name: "sleep", CreateTime: 1003900}
+	l.Observe([]Snapshot{reused}, start.Add(4*time.Second))
+
+	events = l.Events()
+	utils.Equals(t, 4, len(events))
+	utils.Equals(t, []EventKind{ProcessStarted, ProcessExited, ProcessExited, ProcessStarted},
+		[]EventKind{events[0].Kind, events[1].Kind, events[2].Kind, events[3].Kind})
+	utils.Equals(t, "sh", events[1].Process.Name)
+	utils.Equals(t, "", events[1].ExitStatus)
+	utils.Equals(t, 14*time.Second, events[1].Lifetime)
+	utils.Equals(t, "make", events[2].Process.Name)
+	utils.Equals(t, "exited 2", events[2].ExitStatus)
+	utils.Equals(t, "make all", events[2].Process.Cmdline)
+	utils.Equals(t, uint64(8<<20), events[2].PeakRSS)
+	utils.Equals(t, "sleep", events[3].Process.Name)
+}
+
+func TestEventLogFilterAndSize(t *testing.T) {
+	filter, err := NewFilter("^make$", "")
+	utils.Raises(t, err)
+	l := NewEventLog(2, filter)
+
+	l.Observe(nil, time.Unix(0, 0))
+	for pid := int32(1); pid <= 3; pid++ {
+		l.Observe([]Snapshot{{PID: pid, Name: "make"}, {PID: 100 + pid, Name: "cc"}}, time.Unix(int64(pid), 0))
+	}
+
+	// only the latest 2 of the 5 events of make are kept.
+	events, n := l.Since(0)
+	utils.Equals(t, 5, n)
+	utils.Equals(t, 2, len(events))
+	utils.Equals(t, ProcessExited, events[0].Kind)
+	utils.Equals(t, int32(2), events[0].Process.PID)
+	utils.Equals(t, int32(3), events[1].Process.PID)
+
+	events, n = l.Since(4)
+	utils.Equals(t, 5, n)
+	utils.Equals(t, 1, len(events))
+
+	events, _ = l.Since(5)
+	utils.Equals(t, 0, len(events))
+}
+
+func TestExitStatus(t *testing.T) {
+	utils.Equals(t, "", exitStatus(Snapshot{Status: "S"}))
+	utils.Equals(t, "exited 0", exitStatus(Snapshot{Status: "Z"}))
+	utils.Equals(t, "signal 9 (killed)", exitStatus(Snapshot{Status: "Z", ExitCode: 9}))
+}
@@ -0,0 +1,270 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// hostByteOrder is the byte order of the host, which the kernel writes the
+// addresses of the socket tables in.
+var hostByteOrder = func() binary.ByteOrder {
+	one := uint16(1)
+	if *(*byte)(unsafe.Pointer(&one)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// FileDescriptor holds an open file descriptor of a process and, for
+// sockets, the addresses and state of the connection.
+type FileDescriptor struct {
+	Type          string // file, socket, pipe, anon_inode or other.
+	Target        string // the path or description the descriptor links to.
+	Protocol      string // tcp, tcp6, udp, udp6 or unix for known sockets.
+	LocalAddress  string
+	RemoteAddress string
+	State         string
+	FD            int
+}
+
+// socketInfo holds the details of a socket read from /proc/<pid>/net.
+type socketInfo struct {
+	protocol      string
+	localAddress  string
+	remoteAddress string
+	state         string
+}
+
+// tcpStates maps the states of TCP and UDP sockets in /proc/net/{tcp,udp}.
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// unixStates maps the states of unix sockets in /proc/net/unix.
+var unixStates = map[string]string{
+	"01": "UNCONNECTED",
+	"02": "CONNECTING",
+	"03": "CONNECTED",
+	"04": "DISCONNECTING",
+}
+
+// readFDs returns the open file descriptors of a process ordered by number.
+// Sockets are joined with the socket tables of the network namespace of the
+// process.
+func readFDs(procfs string, pid int32) ([]FileDescriptor, error) {
+	dir := filepath.Join(procfs, strconv.Itoa(int(pid)))
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return nil, err
+	}
+
+	// the socket tables are only read if the process has a socket open.
+	var sockets map[string]socketInfo
+
+	fds := make([]FileDescriptor, 0, len(entries))
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(dir, "fd", entry.Name()))
+		if err != nil {
+			// closed while being read.
+			continue
+		}
+
+		desc := FileDescriptor{FD: fd, Target: target, Type: fdType(target)}
+		if desc.Type == "socket" {
+			if sockets == nil {
+				sockets = readSockets(filepath.Join(dir, "net"))
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")
+			if info, ok := sockets[inode]; ok {
+				desc.Protocol = info.protocol
+				desc.LocalAddress = info.localAddress
+				desc.RemoteAddress = info.remoteAddress
+				desc.State = info.state
+			}
+		}
+		fds = append(fds, desc)
+	}
+
+	sort.Slice(fds, func(i, j int) bool {
+		return fds[i].FD < fds[j].FD
+	})
+	return fds, nil
+}
+
+// fdType returns the type of a file descriptor from the target of its link.
+func fdType(target string) string {
+	switch {
+	case strings.HasPrefix(target, "socket:["):
+		return "socket"
+	case strings.HasPrefix(target, "pipe:["):
+		return "pipe"
+	case strings.HasPrefix(target, "anon_inode:"):
+		return "anon_inode"
+	case strings.HasPrefix(target, "/"):
+		return "file"
+	default:
+		return "other"
+	}
+}
+
+// readSockets returns the sockets listed in the tables of a network
+// namespace keyed by their inode. Tables that cannot be read, such as
+// tcp6 on hosts without IPv6, are skipped.
+func readSockets(netDir string) map[string]socketInfo {
+	sockets := make(map[string]socketInfo)
+	for _, protocol := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		readInetSockets(filepath.Join(netDir, protocol), protocol, sockets)
+	}
+	readUnixSockets(filepath.Join(netDir, "unix"), sockets)
+	return sockets
+}
+
+// readInetSockets adds the sockets of a /proc/net/{tcp,tcp6,udp,udp6} table
+// to sockets.
+func readInetSockets(path, protocol string, sockets map[string]socketInfo) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip the header.
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		local, err := parseSocketAddress(fields[1], hostByteOrder)
+		if err != nil {
+			continue
+		}
+		remote, err := parseSocketAddress(fields[2], hostByteOrder)
+		if err != nil {
+			continue
+		}
+		sockets[fields[9]] = socketInfo{
+			protocol:      protocol,
+			localAddress:  local,
+			remoteAddress: remote,
+			state:         tcpStates[fields[3]],
+		}
+	}
+}
+
+// readUnixSockets adds the sockets of /proc/net/unix to sockets.
+func readUnixSockets(path string, sockets map[string]socketInfo) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip the header.
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+		info := socketInfo{protocol: "unix", state: unixStates[fields[5]]}
+		if len(fields) > 7 {
+			info.localAddress = fields[7]
+		}
+		sockets[fields[6]] = info
+	}
+}
+
+// parseSocketAddress parses an address of a /proc/net/{tcp,udp} table such
+// as 0100007F:1F90. The IP address is stored as 32 bit words in the byte
+// order of the host, given by order.
+func parseSocketAddress(s string, order binary.ByteOrder) (string, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("malformed socket address: %q", s)
+	}
+	raw, err := hex.DecodeString(parts[0])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", fmt.Errorf("malformed socket address: %q", s)
+	}
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return "", err
+	}
+
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], order.Uint32(raw[i:]))
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(port))), nil
+}
+
+// readFDLimit returns the soft limit on the number of open files of a
+// process from /proc/<pid>/limits, or 0 if it is unlimited.
+func readFDLimit(procfs string, pid int32) (uint64, error) {
+	f, err := os.Open(filepath.Join(procfs, strconv.Itoa(int(pid)), "limits"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) == 0 {
+			break
+		}
+		if fields[0] == "unlimited" {
+			return 0, nil
+		}
+		return strconv.ParseUint(fields[0], 10, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("open files limit not found for pid %d", pid)
+}
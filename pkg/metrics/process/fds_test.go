@@ -0,0 +1,102 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestParseSocketAddress(t *testing.T) {
+	addr, err := parseSocketAddress("0100007F:1F90", binary.LittleEndian)
+	utils.Raises(t, err)
+	utils.Equals(t, "127.0.0.1:8080", addr)
+
+	addr, err = parseSocketAddress("00000000000000000000000001000000:0016", binary.LittleEndian)
+	utils.Raises(t, err)
+	utils.Equals(t, "[::1]:22", addr)
+
+	// big endian hosts write the words of the address as they are.
+	addr, err = parseSocketAddress("7F000001:1F90", binary.BigEndian)
+	utils.Raises(t, err)
+	utils.Equals(t, "127.0.0.1:8080", addr)
+
+	addr, err = parseSocketAddress("00000000000000000000000000000001:0016", binary.BigEndian)
+	utils.Raises(t, err)
+	utils.Equals(t, "[::1]:22", addr)
+
+	_, err = parseSocketAddress("0100007F", hostByteOrder)
+	utils.Assert(t, err != nil, "expected an error for an address without a port")
+}
+
+func TestReadFDs(t *testing.T) {
+	procfs := t.TempDir()
+	dir := filepath.Join(procfs, "7")
+	utils.Raises(t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+	utils.Raises(t, os.MkdirAll(filepath.Join(dir, "net"), 0755))
+
+	links := map[string]string{
+		"0":  "/dev/null",
+		"1":  "pipe:[100]",
+		"3":  "socket:[200]",
+		"4":  "socket:[300]",
+		"10": "anon_inode:[eventpoll]",
+	}
+	for fd, target := range links {
+		utils.Raises(t, os.Symlink(target, filepath.Join(dir, "fd", fd)))
+	}
+
+	tcp := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+		"   0: 0100007F:1F90 0200000A:01BB 01 00000000:00000000 00:00000000 00000000  1000        0 200 1 0 100 0 0 10 0\n"
+	unix := "Num       RefCount Protocol Flags    Type St Inode Path\n" +
+		"0000000089584ff1: 00000002 00000000 00010000 0001 01 300 /run/grofer.sock\n"
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "net", "tcp"), []byte(tcp), 0644))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "net", "unix"), []byte(unix), 0644))
+
+	fds, err := readFDs(procfs, 7)
+	utils.Raises(t, err)
+	utils.Equals(t, 5, len(fds))
+	utils.Equals(t, []string{"file", "pipe", "socket", "socket", "anon_inode"},
+		[]string{fds[0].Type, fds[1].Type, fds[2].Type, fds[3].Type, fds[4].Type})
+
+	utils.Equals(t, "tcp", fds[2].Protocol)
+	utils.Equals(t, "127.0.0.1:8080", fds[2].LocalAddress)
+	utils.Equals(t, "10.0.0.2:443", fds[2].RemoteAddress)
+	utils.Equals(t, "ESTABLISHED", fds[2].State)
+
+	utils.Equals(t, "unix", fds[3].Protocol)
+	utils.Equals(t, "/run/grofer.sock", fds[3].LocalAddress)
+	utils.Equals(t, "UNCONNECTED", fds[3].State)
+	utils.Equals(t, 10, fds[4].FD)
+}
+
+func TestReadFDLimit(t *testing.T) {
+	procfs := t.TempDir()
+	utils.Raises(t, os.MkdirAll(filepath.Join(procfs, "7"), 0755))
+	limits := "Limit                     Soft Limit           Hard Limit           Units\n" +
+		"Max open files            1024                 1048576              files\n"
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "7", "limits"), []byte(limits), 0644))
+
+	limit, err := readFDLimit(procfs, 7)
+	utils.Raises(t, err)
+	utils.Equals(t, uint64(1024), limit)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Filter selects processes by a regular expression matched against their
+// PID, command name, command line or user name, and by the user running
+// them. A nil Filter matches every process.
+type Filter struct {
+	pattern *regexp.Regexp
+	user    string
+}
+
+// NewFilter returns a Filter for the given regular expression and user name
+// or ID. Either may be empty to match every process.
+func NewFilter(pattern, user string) (*Filter, error) {
+	f := &Filter{user: user}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %v", pattern, err)
+		}
+		f.pattern = re
+	}
+	return f, nil
+}
+
+// String returns the regular expression of the filter.
+func (f *Filter) String() string {
+	if f == nil || f.pattern == nil {
+		return ""
+	}
+	return f.pattern.String()
+}
+
+// User returns the user name or ID the filter selects processes of, or
+// an empty string if it selects the processes of every user.
+func (f *Filter) User() string {
+	if f == nil {
+		return ""
+	}
+	return f.user
+}
+
+// Match reports whether the process is selected by the filter.
+func (f *Filter) Match(p Snapshot) bool {
+	if f == nil {
+		return true
+	}
+
+	uid := strconv.Itoa(int(p.UID))
+	if f.user != "" && f.user != p.Username && f.user != uid {
+		return false
+	}
+	if f.pattern == nil {
+		return true
+	}
+	return f.pattern.MatchString(strconv.Itoa(int(p.PID))) ||
+		f.pattern.MatchString(p.Name) ||
+		f.pattern.MatchString(p.Cmdline) ||
+		f.pattern.MatchString(p.Username)
+}
+
+// Apply returns the processes selected by the filter.
+func (f *Filter) Apply(procs []Snapshot) []Snapshot {
+	if f == nil || (f.pattern == nil && f.user == "") {
+		return procs
+	}
+
+	selected := make([]Snapshot, 0, len(procs))
+	for _, p := range procs {
+		if f.Match(p) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
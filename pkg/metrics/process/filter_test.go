@@ -0,0 +1,64 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestFilter(t *testing.T) {
+	procs := []Snapshot{
+		{PID: 1, Name: "systemd", Cmdline: "/sbin/init splash", Username: "root"},
+		{PID: 42, Name: "make", Cmdline: "make -j8", UID: 1000, Username: "alice"},
+		{PID: 43, Name: "cc1", Cmdline: "/usr/lib/gcc/cc1 main.c", UID: 1000, Username: "alice"},
+	}
+	pids := func(procs []Snapshot) []int32 {
+		selected := []int32{}
+		for _, p := range procs {
+			selected = append(selected, p.PID)
+		}
+		return selected
+	}
+
+	var none *Filter
+	utils.Equals(t, []int32{1, 42, 43}, pids(none.Apply(procs)))
+
+	f, err := NewFilter("^4[0-9]$", "")
+	utils.Raises(t, err)
+	utils.Equals(t, []int32{42, 43}, pids(f.Apply(procs)))
+
+	// matches the command line of cc1.
+	f, err = NewFilter(`main\.c`, "")
+	utils.Raises(t, err)
+	utils.Equals(t, []int32{43}, pids(f.Apply(procs)))
+
+	f, err = NewFilter("", "1000")
+	utils.Raises(t, err)
+	utils.Equals(t, []int32{42, 43}, pids(f.Apply(procs)))
+
+	f, err = NewFilter("init|make", "alice")
+	utils.Raises(t, err)
+	utils.Equals(t, []int32{42}, pids(f.Apply(procs)))
+	utils.Equals(t, "init|make", f.String())
+	utils.Equals(t, "alice", f.User())
+	utils.Equals(t, "", none.User())
+
+	_, err = NewFilter("(", "")
+	utils.Assert(t, err != nil, "expected an error for an invalid regular expression")
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pesos/grofer/pkg/core"
+)
+
+// Guard decides whether a process may be acted upon, ex - signalled or
+// reniced, from the UI. PID 1 and grofer itself are always protected, as
+// are processes whose command name or executable matches one of the
+// patterns of the guard. A read-only Guard refuses every action. A nil
+// Guard only protects PID 1 and grofer itself.
+type Guard struct {
+	patterns []*regexp.Regexp
+	readOnly bool
+}
+
+// NewGuard returns a Guard protecting the processes whose command name or
+// executable matches one of the regular expressions, and refusing every
+// action if readOnly is set.
+func NewGuard(patterns []string, readOnly bool) (*Guard, error) {
+	g := &Guard{readOnly: readOnly}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protected pattern %q: %v", pattern, err)
+		}
+		g.patterns = append(g.patterns, re)
+	}
+	return g, nil
+}
+
+// ReadOnly reports whether every action is refused.
+func (g *Guard) ReadOnly() bool {
+	return g != nil && g.readOnly
+}
+
+// Check returns an error wrapping core.ErrProtectedProcess if the process
+// with the given PID and names, its command name and optionally its
+// executable, may not be acted upon. A process without any name, ex - one
+// that is no longer listed, is refused as it cannot be matched.
+func (g *Guard) Check(pid int32, names ...string) error {
+	switch {
+	case g.ReadOnly():
+		return fmt.Errorf("%w: grofer is read-only", core.ErrProtectedProcess)
+	case pid == 1:
+		return fmt.Errorf("%w: PID 1 is the init process", core.ErrProtectedProcess)
+	case int(pid) == os.Getpid():
+		return fmt.Errorf("%w: PID %d is grofer itself", core.ErrProtectedProcess, pid)
+	}
+
+	named := false
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		named = true
+		if g == nil {
+			continue
+		}
+		for _, re := range g.patterns {
+			if re.MatchString(name) {
+				return fmt.Errorf("%w: %s matches %q", core.ErrProtectedProcess, name, re.String())
+			}
+		}
+	}
+	if !named {
+		return fmt.Errorf("%w: the name of PID %d is unknown", core.ErrProtectedProcess, pid)
+	}
+	return nil
+}
+
+// CheckRunning is like Check for the process currently running with the
+// given PID, whose command name and executable are read just before being
+// matched. The executable of a process of another user is only readable
+// with elevated privileges, in which case only its command name is matched.
+func (g *Guard) CheckRunning(pid int32) error {
+	return g.checkRunning("/proc", pid)
+}
+
+// checkRunning matches the command name and executable read from procfs.
+func (g *Guard) checkRunning(procfs string, pid int32) error {
+	dir := filepath.Join(procfs, strconv.Itoa(int(pid)))
+	comm, _ := ioutil.ReadFile(filepath.Join(dir, "comm"))
+	exe, _ := os.Readlink(filepath.Join(dir, "exe"))
+	return g.Check(pid, strings.TrimSuffix(string(comm), "\n"), exe)
+}
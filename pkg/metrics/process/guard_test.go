@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGuard(t *testing.T) {
+	g, err := NewGuard([]string{"^sshd$", "dockerd"}, false)
+	utils.Raises(t, err)
+
+	for _, p := range []struct {
+		pid  int32
+		name string
+	}{
+		{1, "systemd"},
+		{int32(os.Getpid()), "grofer"},
+		{100, "sshd"},
+		{101, "dockerd"},
+	} {
+		err := g.Check(p.pid, p.name)
+		utils.Assert(t, errors.Is(err, core.ErrProtectedProcess), "expected %d (%s) to be protected", p.pid, p.name)
+	}
+	utils.Raises(t, g.Check(102, "sshd-session"))
+	utils.Raises(t, g.Check(103, "sleep"))
+
+	// the executable is matched along with the command name, and a
+	// process without any name is refused.
+	utils.Assert(t, g.Check(104, "containerd-shim", "/usr/bin/dockerd") != nil, "expected the executable to be matched")
+	utils.Raises(t, g.Check(104, "sleep", ""))
+	utils.Assert(t, errors.Is(g.Check(105, ""), core.ErrProtectedProcess), "expected a process without a name to be refused")
+	utils.Assert(t, g.Check(105) != nil, "expected a process without a name to be refused")
+
+	var nilGuard *Guard
+	utils.Assert(t, nilGuard.Check(1, "init") != nil, "expected PID 1 to be protected")
+	utils.Raises(t, nilGuard.Check(103, "sshd"))
+	utils.Assert(t, nilGuard.Check(103, "") != nil, "expected a process without a name to be refused")
+	utils.Assert(t, !nilGuard.ReadOnly(), "expected a nil guard not to be read-only")
+
+	readOnly, err := NewGuard(nil, true)
+	utils.Raises(t, err)
+	utils.Assert(t, readOnly.ReadOnly(), "expected a read-only guard")
+	utils.Assert(t, readOnly.Check(103, "sleep") != nil, "expected every process to be protected")
+
+	_, err = NewGuard([]string{"("}, false)
+	utils.Assert(t, err != nil, "expected an error for an invalid pattern")
+}
+
+func TestGuardCheckRunning(t *testing.T) {
+	procfs := t.TempDir()
+	g, err := NewGuard([]string{"^/usr/sbin/sshd$", "^postgres$"}, false)
+	utils.Raises(t, err)
+
+	dir := filepath.Join(procfs, "42")
+	utils.Raises(t, os.MkdirAll(dir, 0755))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "comm"), []byte("postgres\n"), 0644))
+	utils.Assert(t, g.checkRunning(procfs, 42) != nil, "expected the command name to be matched")
+
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(dir, "comm"), []byte("sshd-session\n"), 0644))
+	utils.Raises(t, g.checkRunning(procfs, 42))
+	utils.Raises(t, os.Symlink("/usr/sbin/sshd", filepath.Join(dir, "exe")))
+	utils.Assert(t, g.checkRunning(procfs, 42) != nil, "expected the executable to be matched")
+
+	utils.Assert(t, g.checkRunning(procfs, 43) != nil, "expected a process that is not running to be refused")
+}
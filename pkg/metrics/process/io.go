@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IOStat holds the I/O counters of a process from /proc/<pid>/io, counted
+// since it started.
+type IOStat struct {
+	RChar      uint64 `json:"rchar"`      // bytes read by read(2) and alike, including from the page cache.
+	WChar      uint64 `json:"wchar"`      // bytes written by write(2) and alike, including to the page cache.
+	SyscR      uint64 `json:"syscr"`      // read system calls.
+	SyscW      uint64 `json:"syscw"`      // write system calls.
+	ReadBytes  uint64 `json:"readBytes"`  // bytes read from storage.
+	WriteBytes uint64 `json:"writeBytes"` // bytes written to storage.
+}
+
+// IORate holds the I/O counters of a process per second over an interval.
+type IORate struct {
+	RChar      float64 `json:"rchar"`
+	WChar      float64 `json:"wchar"`
+	SyscR      float64 `json:"syscr"`
+	SyscW      float64 `json:"syscw"`
+	ReadBytes  float64 `json:"readBytes"`
+	WriteBytes float64 `json:"writeBytes"`
+}
+
+// readIO returns the I/O counters of a process from /proc/<pid>/io, which
+// is only readable by the owner of the process or with privileges.
+func readIO(path string) (IOStat, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return IOStat{}, err
+	}
+
+	var stat IOStat
+	fields := map[string]*uint64{
+		"rchar:":       &stat.RChar,
+		"wchar:":       &stat.WChar,
+		"syscr:":       &stat.SyscR,
+		"syscw:":       &stat.SyscW,
+		"read_bytes:":  &stat.ReadBytes,
+		"write_bytes:": &stat.WriteBytes,
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		kv := strings.Fields(line)
+		if len(kv) != 2 {
+			continue
+		}
+		if field, ok := fields[kv[0]]; ok {
+			*field, err = strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return IOStat{}, err
+			}
+		}
+	}
+	return stat, nil
+}
+
+// ioRate returns the rates of the I/O counters between two readings taken
+// elapsed apart. A counter that went back has no rate.
+func ioRate(prev, cur IOStat, elapsed time.Duration) IORate {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		return IORate{}
+	}
+	rate := func(prev, cur uint64) float64 {
+		if cur < prev {
+			return 0
+		}
+		return float64(cur-prev) / seconds
+	}
+
+	return IORate{
+		RChar:      rate(prev.RChar, cur.RChar),
+		WChar:      rate(prev.WChar, cur.WChar),
+		SyscR:      rate(prev.SyscR, cur.SyscR),
+		SyscW:      rate(prev.SyscW, cur.SyscW),
+		ReadBytes:  rate(prev.ReadBytes, cur.ReadBytes),
+		WriteBytes: rate(prev.WriteBytes, cur.WriteBytes),
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestReadIO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "io")
+	contents := "rchar: 3201\nwchar: 520\nsyscr: 12\nsyscw: 7\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 4096\n"
+	utils.Raises(t, ioutil.WriteFile(path, []byte(contents), 0644))
+
+	stat, err := readIO(path)
+	utils.Raises(t, err)
+	utils.Equals(t, IOStat{RChar: 3201, WChar: 520, SyscR: 12, SyscW: 7, ReadBytes: 4096, WriteBytes: 8192}, stat)
+
+	utils.Raises(t, ioutil.WriteFile(path, []byte("rchar: lots\n"), 0644))
+	_, err = readIO(path)
+	utils.Assert(t, err != nil, "expected an error for a malformed counter")
+
+	_, err = readIO(filepath.Join(t.TempDir(), "missing"))
+	utils.Assert(t, err != nil, "expected an error for a missing file")
+}
+
+func TestIORate(t *testing.T) {
+	prev := IOStat{RChar: 1000, WChar: 100, SyscR: 10, SyscW: 4, ReadBytes: 4096, WriteBytes: 0}
+	cur := IOStat{RChar: 5000, WChar: 100, SyscR: 30, SyscW: 2, ReadBytes: 12288, WriteBytes: 2048}
+
+	// counters going back, ex - a malformed reading, have no rate.
+	utils.Equals(t, IORate{RChar: 2000, SyscR: 10, ReadBytes: 4096, WriteBytes: 1024}, ioRate(prev, cur, 2*time.Second))
+	utils.Equals(t, IORate{}, ioRate(prev, cur, 0))
+}
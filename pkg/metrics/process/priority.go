@@ -0,0 +1,292 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Bounds of the nice value of a process.
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// IOClass is an I/O scheduling class of the kernel.
+type IOClass int
+
+// I/O scheduling classes, as numbered by ioprio_set(2).
+const (
+	IOClassNone IOClass = iota
+	IOClassRealtime
+	IOClassBestEffort
+	IOClassIdle
+)
+
+// ioClassNames maps the names accepted by ParseIOPriority to a class.
+var ioClassNames = map[string]IOClass{
+	"none":        IOClassNone,
+	"realtime":    IOClassRealtime,
+	"rt":          IOClassRealtime,
+	"best-effort": IOClassBestEffort,
+	"be":          IOClassBestEffort,
+	"idle":        IOClassIdle,
+}
+
+// String returns the name of the class.
+func (c IOClass) String() string {
+	switch c {
+	case IOClassNone:
+		return "none"
+	case IOClassRealtime:
+		return "realtime"
+	case IOClassBestEffort:
+		return "best-effort"
+	case IOClassIdle:
+		return "idle"
+	default:
+		return "unknown"
+	}
+}
+
+// IOPriority is the I/O scheduling class of a process and its priority
+// level within the class, 0 being the highest. The idle class and the none
+// class, which follows the nice value of the process, have no level.
+type IOPriority struct {
+	Class IOClass
+	Level int
+}
+
+// String returns the priority in the form accepted by ParseIOPriority.
+func (p IOPriority) String() string {
+	if p.Class == IOClassRealtime || p.Class == IOClassBestEffort {
+		return fmt.Sprintf("%s:%d", p.Class, p.Level)
+	}
+	return p.Class.String()
+}
+
+// Constants of ioprio_get(2) and ioprio_set(2).
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+	ioprioLevelMask  = 1<<ioprioClassShift - 1
+	ioprioMaxLevel   = 7
+)
+
+// cpuSetSize is the number of CPUs that an affinity mask can hold.
+const cpuSetSize = 1024
+
+// ParseNice parses and validates a nice value.
+func ParseNice(s string) (int, error) {
+	nice, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid nice value: %q", s)
+	}
+	if nice < MinNice || nice > MaxNice {
+		return 0, fmt.Errorf("nice value must be between %d and %d", MinNice, MaxNice)
+	}
+	return nice, nil
+}
+
+// ParseIOPriority parses an I/O priority written as the name of the class
+// followed by the level for the realtime and best-effort classes, like
+// "best-effort:4", "rt:0" or "idle". The level defaults to 4.
+func ParseIOPriority(s string) (IOPriority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	name, level := s, ""
+	if i := strings.IndexAny(s, ": "); i != -1 {
+		name, level = s[:i], strings.TrimSpace(s[i+1:])
+	}
+
+	class, ok := ioClassNames[name]
+	if !ok {
+		return IOPriority{}, fmt.Errorf("unknown I/O scheduling class: %q", name)
+	}
+	prio := IOPriority{Class: class}
+	if class != IOClassRealtime && class != IOClassBestEffort {
+		if level != "" {
+			return IOPriority{}, fmt.Errorf("the %s class has no priority level", class)
+		}
+		return prio, nil
+	}
+
+	prio.Level = 4
+	if level != "" {
+		l, err := strconv.Atoi(level)
+		if err != nil || l < 0 || l > ioprioMaxLevel {
+			return IOPriority{}, fmt.Errorf("priority level must be between 0 and %d", ioprioMaxLevel)
+		}
+		prio.Level = l
+	}
+	return prio, nil
+}
+
+// ParseCPUList parses a list of CPUs and ranges of CPUs like "0-3,6", as
+// used by taskset(1), and returns the CPUs in order. Every CPU must be
+// lower than numCPU.
+func ParseCPUList(s string, numCPU int) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU: %q", part)
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU range: %q", part)
+			}
+		}
+		if first < 0 || last >= numCPU || last >= cpuSetSize {
+			return nil, fmt.Errorf("CPUs must be between 0 and %d", numCPU-1)
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			seen[cpu] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, errors.New("at least one CPU is required")
+	}
+
+	cpus := make([]int, 0, len(seen))
+	for cpu := range seen {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// FormatCPUList returns a sorted list of CPUs in the form accepted by
+// ParseCPUList, with consecutive CPUs written as ranges.
+func FormatCPUList(cpus []int) string {
+	parts := []string{}
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(cpus[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// SetNice sets the nice value of every thread of a process.
+func SetNice(pid int32, nice int) error {
+	return forEachThread(pid, func(tid int) error {
+		return syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice)
+	})
+}
+
+// GetIOPriority returns the I/O priority of a process.
+func GetIOPriority(pid int32) (IOPriority, error) {
+	r, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(pid), 0)
+	if errno != 0 {
+		return IOPriority{}, errno
+	}
+	return IOPriority{
+		Class: IOClass(r >> ioprioClassShift),
+		Level: int(r & ioprioLevelMask),
+	}, nil
+}
+
+// SetIOPriority sets the I/O priority of every thread of a process.
+func SetIOPriority(pid int32, prio IOPriority) error {
+	value := uintptr(prio.Class)<<ioprioClassShift | uintptr(prio.Level)
+	return forEachThread(pid, func(tid int) error {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), value)
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	})
+}
+
+// GetAffinity returns the CPUs that a process is allowed to run on.
+func GetAffinity(pid int32) ([]int, error) {
+	var mask [cpuSetSize / 64]uint64
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, uintptr(pid),
+		uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return nil, errno
+	}
+
+	cpus := []int{}
+	for cpu := 0; cpu < cpuSetSize; cpu++ {
+		if mask[cpu/64]&(1<<uint(cpu%64)) != 0 {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// SetAffinity restricts every thread of a process to the given CPUs.
+func SetAffinity(pid int32, cpus []int) error {
+	var mask [cpuSetSize / 64]uint64
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << uint(cpu%64)
+	}
+	return forEachThread(pid, func(tid int) error {
+		_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, uintptr(tid),
+			uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	})
+}
+
+// forEachThread calls fn with the TID of every thread of a process, as
+// the kernel applies priorities and affinity to single threads. Threads
+// that exit meanwhile are skipped.
+func forEachThread(pid int32, fn func(tid int) error) error {
+	tids := []int{int(pid)}
+	entries, err := ioutil.ReadDir(filepath.Join("/proc", strconv.Itoa(int(pid)), "task"))
+	if err == nil {
+		for _, entry := range entries {
+			tid, err := strconv.Atoi(entry.Name())
+			if err == nil && tid != int(pid) {
+				tids = append(tids, tid)
+			}
+		}
+	}
+
+	for _, tid := range tids {
+		if err := fn(tid); err != nil && (tid == int(pid) || err != syscall.ESRCH) {
+			return err
+		}
+	}
+	return nil
+}
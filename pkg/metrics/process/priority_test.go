@@ -0,0 +1,82 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestParseNice(t *testing.T) {
+	nice, err := ParseNice(" -5 ")
+	utils.Raises(t, err)
+	utils.Equals(t, -5, nice)
+
+	for _, s := range []string{"", "ten", "20", "-21"} {
+		_, err := ParseNice(s)
+		utils.Assert(t, err != nil, "expected an error for %q", s)
+	}
+}
+
+func TestParseIOPriority(t *testing.T) {
+	cases := map[string]IOPriority{
+		"best-effort:2": {Class: IOClassBestEffort, Level: 2},
+		"be 7":          {Class: IOClassBestEffort, Level: 7},
+		"RT":            {Class: IOClassRealtime, Level: 4},
+		"idle":          {Class: IOClassIdle},
+		"none":          {Class: IOClassNone},
+	}
+	for s, want := range cases {
+		prio, err := ParseIOPriority(s)
+		utils.Raises(t, err)
+		utils.Equals(t, want, prio)
+	}
+
+	for _, s := range []string{"", "fast", "be:8", "be:-1", "idle:3"} {
+		_, err := ParseIOPriority(s)
+		utils.Assert(t, err != nil, "expected an error for %q", s)
+	}
+
+	utils.Equals(t, "best-effort:2", IOPriority{Class: IOClassBestEffort, Level: 2}.String())
+	utils.Equals(t, "idle", IOPriority{Class: IOClassIdle}.String())
+}
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := ParseCPUList("6, 0-3,2", 8)
+	utils.Raises(t, err)
+	utils.Equals(t, []int{0, 1, 2, 3, 6}, cpus)
+	utils.Equals(t, "0-3,6", FormatCPUList(cpus))
+
+	for _, s := range []string{"", ",", "a", "3-1", "0-8", "-1"} {
+		_, err := ParseCPUList(s, 8)
+		utils.Assert(t, err != nil, "expected an error for %q", s)
+	}
+}
+
+func TestAffinityRoundTrip(t *testing.T) {
+	pid := int32(os.Getpid())
+	cpus, err := GetAffinity(pid)
+	utils.Raises(t, err)
+	utils.Assert(t, len(cpus) > 0, "expected at least one CPU")
+
+	utils.Raises(t, SetAffinity(pid, cpus))
+	got, err := GetAffinity(pid)
+	utils.Raises(t, err)
+	utils.Equals(t, cpus, got)
+}
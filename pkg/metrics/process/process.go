@@ -17,19 +17,73 @@ limitations under the License.
 package process
 
 import (
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
 	proc "github.com/shirou/gopsutil/process"
 )
 
+// Reading is a costly reading of a process that is only taken when asked
+// for, ex - while the panel showing it is visible.
+type Reading uint32
+
+const (
+	// ReadThreads reads every thread of the process.
+	ReadThreads Reading = 1 << iota
+	// ReadFDs reads every open file descriptor of the process and the
+	// socket tables to describe its sockets.
+	ReadFDs
+	// ReadDetails reads the arguments, environment, limits, cgroups,
+	// namespaces and scheduling of the process.
+	ReadDetails
+)
+
+// Readings is the set of costly readings taken when updating a process. A
+// sink can turn readings on and off while another goroutine updates the
+// process.
+type Readings struct {
+	set uint32
+}
+
+// Set turns the given readings on or off.
+func (r *Readings) Set(reading Reading, on bool) {
+	for {
+		old := atomic.LoadUint32(&r.set)
+		set := old &^ uint32(reading)
+		if on {
+			set = old | uint32(reading)
+		}
+		if atomic.CompareAndSwapUint32(&r.set, old, set) {
+			return
+		}
+	}
+}
+
+// Has reports whether all of the given readings are on. A nil Readings
+// has none on.
+func (r *Readings) Has(reading Reading) bool {
+	return r != nil && Reading(atomic.LoadUint32(&r.set))&reading == reading
+}
+
 // Process type contains as fields all the information extracted from the kernel.
 type Process struct {
 	Proc           *proc.Process
+	Readings       *Readings // costly readings taken by UpdateProcInfo, none if nil.
 	MemoryInfo     *proc.MemoryInfoStat
 	PageFault      *proc.PageFaultsStat
 	NumCtxSwitches *proc.NumCtxSwitchesStat
+	IO             *IOStat // nil if /proc/<pid>/io is not readable.
+	IORate         IORate  // per second since the previous update.
 	Exe            string
 	Name           string
 	Status         string
 	Children       []*proc.Process
+	Threads        []Thread         // only read with ReadThreads.
+	FDs            []FileDescriptor // only read with ReadFDs.
+	FDLimit        uint64           // 0 if unlimited, only read with ReadFDs.
+	Details        Details          // only read with ReadDetails.
 	Gids           []int32
 	CPUAffinity    []int32
 	CreateTime     int64
@@ -40,6 +94,8 @@ type Process struct {
 	IsRunning      bool
 	Foreground     bool
 	Background     bool
+	threads        *threadSampler
+	ioTime         time.Time
 }
 
 // NewProcess return a Process variable for a given PID
@@ -69,7 +125,9 @@ func (p *Process) UpdateProcInfo() {
 		p.IsRunning = tempIsRunning
 	}
 
-	tempCPUPercent, err := p.Proc.CPUPercent()
+	// CPU usage since the previous update rather than since the
+	// process started.
+	tempCPUPercent, err := p.Proc.Percent(0)
 	if err == nil {
 		p.CPUPercent = tempCPUPercent
 	}
@@ -140,6 +198,70 @@ func (p *Process) UpdateProcInfo() {
 	if err == nil {
 		p.CPUAffinity = tempAffinity
 	}
+
+	tempIO, err := readIO(filepath.Join("/proc", strconv.Itoa(int(p.Proc.Pid)), "io"))
+	if err == nil {
+		// the first update has no previous reading to measure a rate from.
+		now := time.Now()
+		if p.IO != nil {
+			p.IORate = ioRate(*p.IO, tempIO, now.Sub(p.ioTime))
+		}
+		p.IO, p.ioTime = &tempIO, now
+	}
+
+	p.updateReadings()
+}
+
+// updateReadings takes the costly readings that are on, and forgets those
+// that are off.
+func (p *Process) updateReadings() {
+	if p.Readings.Has(ReadThreads) {
+		if p.threads == nil {
+			p.threads = newThreadSampler("/proc", p.Proc.Pid)
+		}
+		tempThreads, err := p.threads.sample()
+		if err == nil {
+			p.Threads = tempThreads
+		}
+	} else {
+		// thread CPU usage is measured from the next reading taken.
+		p.threads, p.Threads = nil, nil
+	}
+
+	if p.Readings.Has(ReadFDs) {
+		tempFDs, err := readFDs("/proc", p.Proc.Pid)
+		if err == nil {
+			p.FDs = tempFDs
+		}
+		tempFDLimit, err := readFDLimit("/proc", p.Proc.Pid)
+		if err == nil {
+			p.FDLimit = tempFDLimit
+		}
+	} else {
+		p.FDs, p.FDLimit = nil, 0
+	}
+
+	if !p.Readings.Has(ReadDetails) {
+		p.Details = Details{}
+		return
+	}
+	p.Details = readDetails("/proc", p.Proc.Pid)
+	tempIOPriority, err := GetIOPriority(p.Proc.Pid)
+	if err == nil {
+		p.Details.Scheduling.IOPriority = tempIOPriority.String()
+	}
+	tempSchedAffinity, err := GetAffinity(p.Proc.Pid)
+	if err == nil {
+		p.Details.Scheduling.CPUAffinity = tempSchedAffinity
+	}
+}
+
+// Copy returns a copy of the process for consumers to read while the
+// process is updated again. Updates replace the values of the fields
+// rather than changing them in place, so a shallow copy is enough.
+func (p *Process) Copy() *Process {
+	c := *p
+	return &c
 }
 
 // InitAllProcs initialises the set of currently running processes in the system.
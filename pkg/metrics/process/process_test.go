@@ -0,0 +1,56 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestReadings(t *testing.T) {
+	var none *Readings
+	utils.Assert(t, !none.Has(ReadThreads), "expected a nil Readings to have no reading on")
+
+	readings := &Readings{}
+	readings.Set(ReadThreads|ReadDetails, true)
+	utils.Assert(t, readings.Has(ReadThreads|ReadDetails), "expected threads and details to be on")
+	utils.Assert(t, !readings.Has(ReadFDs), "expected open files to be off")
+
+	readings.Set(ReadThreads, false)
+	utils.Assert(t, !readings.Has(ReadThreads), "expected threads to be off")
+	utils.Assert(t, readings.Has(ReadDetails), "expected details to stay on")
+}
+
+func TestUpdateProcInfoReadings(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	utils.Raises(t, err)
+
+	p.UpdateProcInfo()
+	utils.Assert(t, p.Threads == nil && p.FDs == nil, "expected no threads or open files without readings, got %d and %d", len(p.Threads), len(p.FDs))
+
+	p.Readings = &Readings{}
+	p.Readings.Set(ReadThreads|ReadFDs, true)
+	p.UpdateProcInfo()
+	utils.Assert(t, len(p.Threads) > 0, "expected the threads to be read")
+	utils.Assert(t, len(p.FDs) > 0, "expected the open files to be read")
+
+	p.Readings.Set(ReadThreads|ReadFDs, false)
+	p.UpdateProcInfo()
+	utils.Assert(t, p.Threads == nil && p.FDs == nil, "expected the threads and open files to be forgotten")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Selector selects a process to follow by its command name or the name of
+// its executable, or by a regular expression matched against the path of
+// its executable and its command line.
+type Selector struct {
+	procfs  string
+	name    string
+	pattern *regexp.Regexp
+}
+
+// NewSelector returns a Selector for the given name or regular expression,
+// only one of which may be set.
+func NewSelector(name, pattern string) (*Selector, error) {
+	if (name == "") == (pattern == "") {
+		return nil, fmt.Errorf("a process is selected by either a name or a regular expression")
+	}
+
+	s := &Selector{procfs: "/proc", name: name}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match %q: %v", pattern, err)
+		}
+		s.pattern = re
+	}
+	return s, nil
+}
+
+// String describes the processes selected.
+func (s *Selector) String() string {
+	if s.pattern != nil {
+		return fmt.Sprintf("matching %q", s.pattern.String())
+	}
+	return fmt.Sprintf("named %q", s.name)
+}
+
+// Match reports whether the process is selected. The executable of
+// processes of other users cannot be read without privileges, in which
+// case the first argument of the command line stands in for it.
+func (s *Selector) Match(p Snapshot) bool {
+	exe, err := os.Readlink(filepath.Join(s.procfs, strconv.Itoa(int(p.PID)), "exe"))
+	if err != nil {
+		exe = strings.SplitN(p.Cmdline, " ", 2)[0]
+	}
+
+	if s.pattern != nil {
+		return s.pattern.MatchString(exe) || s.pattern.MatchString(p.Cmdline)
+	}
+	return s.name == p.Name || (exe != "" && s.name == filepath.Base(exe))
+}
+
+// Newest returns the most recently started process that is selected,
+// leaving out zombies and grofer itself.
+func (s *Selector) Newest(procs []Snapshot) (Snapshot, bool) {
+	self := int32(os.Getpid())
+
+	var newest Snapshot
+	found := false
+	for _, p := range procs {
+		if p.PID == self || p.Status == "Z" || !s.Match(p) {
+			continue
+		}
+		if !found || p.CreateTime > newest.CreateTime ||
+			(p.CreateTime == newest.CreateTime && p.PID > newest.PID) {
+			newest, found = p, true
+		}
+	}
+	return newest, found
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestSelector(t *testing.T) {
+	procfs := t.TempDir()
+	utils.Raises(t, os.MkdirAll(filepath.Join(procfs, "10"), 0755))
+	utils.Raises(t, os.Symlink("/usr/sbin/nginx", filepath.Join(procfs, "10", "exe")))
+
+	procs := []Snapshot{
+		{PID: 10, Name: "nginx: master", Cmdline: "nginx: master process", CreateTime: 100},
+		{PID: 11, Name: "nginx", Cmdline: "nginx: worker process", CreateTime: 200},
+		{PID: 12, Name: "nginx", Cmdline: "nginx: worker process", CreateTime: 300, Status: "Z"},
+		{PID: int32(os.Getpid()), Name: "grofer", Cmdline: "grofer proc --match nginx", CreateTime: 400},
+		{PID: 13, Name: "bash", Cmdline: "/bin/bash", CreateTime: 500},
+	}
+
+	byName, err := NewSelector("nginx", "")
+	utils.Raises(t, err)
+	byName.procfs = procfs
+
+	// the name matches the executable when the command name differs.
+	utils.Assert(t, byName.Match(procs[0]), "expected a match on the executable")
+	utils.Assert(t, !byName.Match(procs[4]), "expected bash not to match")
+
+	// zombies and grofer itself are left out.
+	newest, ok := byName.Newest(procs)
+	utils.Assert(t, ok, "expected a match")
+	utils.Equals(t, int32(11), newest.PID)
+
+	byPattern, err := NewSelector("", "sbin/ng")
+	utils.Raises(t, err)
+	byPattern.procfs = procfs
+	newest, ok = byPattern.Newest(procs)
+	utils.Assert(t, ok, "expected a match on the executable path")
+	utils.Equals(t, int32(10), newest.PID)
+
+	_, ok = byPattern.Newest(procs[1:])
+	utils.Assert(t, !ok, "expected no match")
+
+	_, err = NewSelector("nginx", "nginx")
+	utils.Assert(t, err != nil, "expected an error selecting by both a name and a regular expression")
+	_, err = NewSelector("", "(")
+	utils.Assert(t, err != nil, "expected an error for an invalid regular expression")
+}
@@ -0,0 +1,508 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is the number of clock ticks per second in which the kernel
+// reports CPU times in /proc/<pid>/stat (USER_HZ).
+const clockTicks = 100
+
+// maxCommLen is the length the kernel cuts the command name of a process
+// to, TASK_COMM_LEN less the terminating NUL.
+const maxCommLen = 15
+
+// Snapshot holds the state of a single process read from /proc in one pass.
+type Snapshot struct {
+	Name          string
+	Cmdline       string
+	Username      string
+	Status        string
+	Cgroup        string // path of the cgroup, empty if /proc/<pid>/cgroup is not readable.
+	CreateTime    int64  // milliseconds since the epoch.
+	RSS           uint64
+	PeakRSS       uint64        // highest RSS since the start, zero if not reported.
+	CPUTime       time.Duration // user and system time since the start.
+	VMS           uint64
+	IO            IOStat  // zero if /proc/<pid>/io is not readable.
+	IORate        IORate  // per second since the previous snapshot.
+	IOReadable    bool    // whether /proc/<pid>/io was readable.
+	CPUPercent    float64 // share of a single CPU since the previous snapshot.
+	PID           int32
+	PPID          int32
+	UID           int32
+	Nice          int32
+	NumThreads    int32
+	ExitCode      int32 // wait status, only reported for zombies (Status "Z").
+	MemoryPercent float32
+	Foreground    bool
+}
+
+// Key identifies a process across snapshots, so that a PID reused by a
+// different process is not mistaken for the process seen before.
+type Key struct {
+	PID        int32
+	CreateTime int64 // milliseconds since the epoch.
+}
+
+// Key returns the key identifying the process.
+func (s Snapshot) Key() Key {
+	return Key{PID: s.PID, CreateTime: s.CreateTime}
+}
+
+// newKey returns the key of the process or thread with the given ID that
+// started startTime clock ticks after boot.
+func newKey(id int32, bootTime time.Time, startTime uint64) Key {
+	createTime := bootTime.Add(ticksToDuration(startTime))
+	return Key{PID: id, CreateTime: createTime.UnixNano() / int64(time.Millisecond)}
+}
+
+// prevSample holds the counters of a process in the previous snapshot.
+type prevSample struct {
+	cpuTicks   uint64
+	io         IOStat
+	ioReadable bool
+}
+
+// statFields holds the fields of /proc/<pid>/stat used by a Snapshot.
+type statFields struct {
+	name       string
+	state      string
+	ppid       int32
+	pgrp       int32
+	tpgid      int32
+	nice       int32
+	numThreads int32
+	cpuTicks   uint64 // utime + stime.
+	startTime  uint64 // clock ticks since boot.
+	vsize      uint64
+	rssPages   uint64
+	processor  int32 // CPU last run on, -1 if not reported.
+	rtPriority int32
+	policy     int32 // scheduling policy, SCHED_OTHER if not reported.
+	exitCode   int32 // wait status of a zombie, zero if not reported.
+}
+
+// foreground reports whether the process group of the process is the
+// foreground process group of its controlling terminal.
+func (s statFields) foreground() bool {
+	return s.pgrp == s.tpgid
+}
+
+// Sampler takes snapshots of all running processes. It keeps the CPU times
+// of the previous snapshot so that CPU usage is computed per interval
+// rather than averaged over the lifetime of a process.
+type Sampler struct {
+	procfs   string
+	now      func() time.Time
+	bootTime time.Time
+	prev     map[Key]prevSample
+	prevTime time.Time
+	// usernames caches the names of the users looked up so far.
+	usernames map[int32]string
+	mu        sync.Mutex
+}
+
+// NewSampler returns a Sampler reading from /proc.
+func NewSampler() *Sampler {
+	return &Sampler{procfs: "/proc", now: time.Now, usernames: make(map[int32]string)}
+}
+
+// Snapshot reads every process once and returns their state ordered by PID.
+// Processes that exit while being read are skipped.
+func (s *Sampler) Snapshot() ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bootTime.IsZero() {
+		bootTime, err := readBootTime(s.procfs)
+		if err != nil {
+			return nil, err
+		}
+		s.bootTime = bootTime
+	}
+
+	memTotal, err := readMemTotal(s.procfs)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(s.procfs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	pageSize := uint64(os.Getpagesize())
+	cur := make(map[Key]prevSample, len(s.prev))
+	snapshots := make([]Snapshot, 0, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(s.procfs, entry.Name())
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		stat, err := parseStat(contents)
+		if err != nil {
+			continue
+		}
+		uid, peakRSS, err := readStatus(filepath.Join(dir, "status"))
+		if err != nil {
+			continue
+		}
+		cmdline, argv0, err := readCmdline(filepath.Join(dir, "cmdline"))
+		if err != nil {
+			continue
+		}
+
+		createTime := s.bootTime.Add(ticksToDuration(stat.startTime))
+		key := newKey(int32(pid), s.bootTime, stat.startTime)
+
+		// the I/O counters of processes of other users are only
+		// readable with elevated privileges.
+		ioStat, err := readIO(filepath.Join(dir, "io"))
+		ioReadable := err == nil
+		cur[key] = prevSample{cpuTicks: stat.cpuTicks, io: ioStat, ioReadable: ioReadable}
+
+		// processes seen for the first time are measured from their start.
+		prev, since := prevSample{ioReadable: true}, createTime
+		if sample, ok := s.prev[key]; ok {
+			prev, since = sample, s.prevTime
+		}
+		var rate IORate
+		if ioReadable && prev.ioReadable {
+			rate = ioRate(prev.io, ioStat, now.Sub(since))
+		}
+		cgroup, _ := readCgroup(filepath.Join(dir, "cgroup"))
+
+		snapshot := Snapshot{
+			PID:        int32(pid),
+			PPID:       stat.ppid,
+			UID:        uid,
+			Username:   s.username(uid),
+			Name:       fullName(dir, stat.name, argv0),
+			Cmdline:    cmdline,
+			Status:     stat.state,
+			Cgroup:     cgroup,
+			Nice:       stat.nice,
+			NumThreads: stat.numThreads,
+			CreateTime: key.CreateTime,
+			RSS:        stat.rssPages * pageSize,
+			PeakRSS:    peakRSS,
+			VMS:        stat.vsize,
+			IO:         ioStat,
+			IORate:     rate,
+			IOReadable: ioReadable,
+			CPUPercent: cpuPercent(prev.cpuTicks, stat.cpuTicks, now.Sub(since)),
+			CPUTime:    ticksToDuration(stat.cpuTicks),
+			ExitCode:   stat.exitCode,
+			Foreground: stat.foreground(),
+		}
+		if memTotal > 0 {
+			snapshot.MemoryPercent = float32(100 * float64(snapshot.RSS) / float64(memTotal))
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].PID < snapshots[j].PID
+	})
+
+	s.prev = cur
+	s.prevTime = now
+	return snapshots, nil
+}
+
+// username returns the name of the user with the given ID, or the ID itself
+// if the user cannot be looked up.
+func (s *Sampler) username(uid int32) string {
+	if name, ok := s.usernames[uid]; ok {
+		return name
+	}
+
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	s.usernames[uid] = name
+	return name
+}
+
+// cpuPercent returns the CPU usage in percent of a single CPU between two
+// readings of the CPU time of a process, elapsed apart.
+func cpuPercent(prevTicks, curTicks uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || curTicks < prevTicks {
+		return 0
+	}
+	return 100 * ticksToDuration(curTicks-prevTicks).Seconds() / elapsed.Seconds()
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicks
+}
+
+// parseStat parses the contents of /proc/<pid>/stat. The command name is
+// enclosed in parentheses and may itself contain spaces and parentheses, so
+// the remaining fields are split after the last closing parenthesis.
+func parseStat(contents []byte) (statFields, error) {
+	var stat statFields
+
+	start := bytes.IndexByte(contents, '(')
+	end := bytes.LastIndexByte(contents, ')')
+	if start < 0 || end < start {
+		return stat, fmt.Errorf("malformed stat: %q", contents)
+	}
+	stat.name = string(contents[start+1 : end])
+
+	// fields[0] is the state, the third field described in proc(5).
+	fields := strings.Fields(string(contents[end+1:]))
+	if len(fields) < 22 {
+		return stat, fmt.Errorf("malformed stat: %q", contents)
+	}
+	stat.state = fields[0]
+
+	ints := make([]int64, len(fields))
+	for _, i := range []int{1, 2, 5, 11, 12, 16, 17, 19, 20, 21} {
+		v, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return stat, err
+		}
+		ints[i] = v
+	}
+	stat.ppid = int32(ints[1])
+	stat.pgrp = int32(ints[2])
+	stat.tpgid = int32(ints[5])
+	stat.cpuTicks = uint64(ints[11] + ints[12])
+	stat.nice = int32(ints[16])
+	stat.numThreads = int32(ints[17])
+	stat.startTime = uint64(ints[19])
+	stat.vsize = uint64(ints[20])
+	stat.rssPages = uint64(ints[21])
+
+	stat.processor = -1
+	if len(fields) > 36 {
+		processor, err := strconv.ParseInt(fields[36], 10, 32)
+		if err != nil {
+			return stat, err
+		}
+		stat.processor = int32(processor)
+	}
+	if len(fields) > 38 {
+		rtPriority, err := strconv.ParseInt(fields[37], 10, 32)
+		if err != nil {
+			return stat, err
+		}
+		policy, err := strconv.ParseInt(fields[38], 10, 32)
+		if err != nil {
+			return stat, err
+		}
+		stat.rtPriority, stat.policy = int32(rtPriority), int32(policy)
+	}
+	if len(fields) > 49 {
+		exitCode, err := strconv.ParseInt(fields[49], 10, 32)
+		if err != nil {
+			return stat, err
+		}
+		stat.exitCode = int32(exitCode)
+	}
+	return stat, nil
+}
+
+// readStatus returns the real user ID and the peak RSS in bytes from
+// /proc/<pid>/status. Kernel threads do not report a peak RSS.
+func readStatus(path string) (uid int32, peakRSS uint64, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	foundUID := false
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "Uid:":
+			v, err := strconv.ParseInt(fields[1], 10, 32)
+			if err != nil {
+				return 0, 0, err
+			}
+			uid, foundUID = int32(v), true
+		case "VmHWM:":
+			kib, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			peakRSS = kib * 1024
+		}
+	}
+	if !foundUID {
+		return 0, 0, fmt.Errorf("Uid: not found in %s", path)
+	}
+	return uid, peakRSS, nil
+}
+
+// readCmdline returns the command line from /proc/<pid>/cmdline with its
+// arguments separated by spaces, and its first argument. Both are empty
+// for kernel threads.
+func readCmdline(path string) (cmdline, argv0 string, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	contents = bytes.TrimRight(contents, "\x00")
+	first := contents
+	if i := bytes.IndexByte(contents, 0); i != -1 {
+		first = contents[:i]
+	}
+	return string(bytes.ReplaceAll(contents, []byte{0}, []byte{' '})), string(first), nil
+}
+
+// fullName returns the name of a process from its command name, which the
+// kernel cuts to maxCommLen. A command name that may have been cut is
+// replaced by the basename of the first argument of the process, or of its
+// executable if it has no arguments, when that starts with the command name.
+func fullName(dir, comm, argv0 string) string {
+	if len(comm) < maxCommLen {
+		return comm
+	}
+	path := argv0
+	if path == "" {
+		exe, err := os.Readlink(filepath.Join(dir, "exe"))
+		if err != nil {
+			return comm
+		}
+		path = strings.TrimSuffix(exe, " (deleted)")
+	}
+	if name := filepath.Base(path); strings.HasPrefix(name, comm) {
+		return name
+	}
+	return comm
+}
+
+// readCgroup returns the path of the cgroup of a process from
+// /proc/<pid>/cgroup, preferring the unified hierarchy of cgroup v2 over
+// the first hierarchy of cgroup v1.
+func readCgroup(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	cgroup := ""
+	for _, line := range strings.Split(string(contents), "\n") {
+		// each line is hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[0] == "0" && fields[1] == "" {
+			return fields[2], nil
+		}
+		if cgroup == "" {
+			cgroup = fields[2]
+		}
+	}
+	return cgroup, nil
+}
+
+// CreateTime returns the start time of the running process with the given
+// PID in milliseconds since the epoch, as reported in Snapshot.CreateTime.
+func CreateTime(pid int32) (int64, error) {
+	return readCreateTime("/proc", pid)
+}
+
+// readCreateTime returns the start time of a process from its stat.
+func readCreateTime(procfs string, pid int32) (int64, error) {
+	bootTime, err := readBootTime(procfs)
+	if err != nil {
+		return 0, err
+	}
+	contents, err := ioutil.ReadFile(filepath.Join(procfs, strconv.Itoa(int(pid)), "stat"))
+	if err != nil {
+		return 0, err
+	}
+	stat, err := parseStat(contents)
+	if err != nil {
+		return 0, err
+	}
+	return newKey(pid, bootTime, stat.startTime).CreateTime, nil
+}
+
+// readBootTime returns the boot time recorded in /proc/stat.
+func readBootTime(procfs string) (time.Time, error) {
+	v, err := readField(filepath.Join(procfs, "stat"), "btime")
+	if err != nil {
+		return time.Time{}, err
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// readMemTotal returns the total amount of memory in bytes.
+func readMemTotal(procfs string) (uint64, error) {
+	v, err := readField(filepath.Join(procfs, "meminfo"), "MemTotal:")
+	if err != nil {
+		return 0, err
+	}
+	kib, err := strconv.ParseUint(v, 10, 64)
+	return kib * 1024, err
+}
+
+// readField returns the first value of the line starting with key in a
+// file of "key value..." lines.
+func readField(path, key string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == key {
+			return fields[1], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s not found in %s", key, path)
+}
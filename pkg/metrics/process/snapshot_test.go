@@ -0,0 +1,184 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestParseStat(t *testing.T) {
+	stat, err := parseStat([]byte("42 (tmux: server (1)) S 1 42 42 0 -1 4194560 1 0 0 0 30 12 0 0 20 -5 3 0 500 1024 7 18446744073709551615\n"))
+	utils.Raises(t, err)
+	utils.Equals(t, "tmux: server (1)", stat.name)
+	utils.Equals(t, "S", stat.state)
+	utils.Equals(t, int32(1), stat.ppid)
+	utils.Equals(t, uint64(42), stat.cpuTicks)
+	utils.Equals(t, int32(-5), stat.nice)
+	utils.Equals(t, int32(3), stat.numThreads)
+	utils.Equals(t, uint64(500), stat.startTime)
+	utils.Equals(t, uint64(1024), stat.vsize)
+	utils.Equals(t, uint64(7), stat.rssPages)
+	utils.Assert(t, !stat.foreground(), "expected a process without a terminal to be in the background")
+
+	// zombies report their wait status in the 52nd field.
+	stat, err = parseStat([]byte("9 (sh) Z 1 9 9 0 -1 0 0 0 0 0 3 1 0 0 20 0 1 0 500 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 256\n"))
+	utils.Raises(t, err)
+	utils.Equals(t, "Z", stat.state)
+	utils.Equals(t, int32(256), stat.exitCode)
+
+	_, err = parseStat([]byte("42 (sh) S 1"))
+	utils.Assert(t, err != nil, "expected an error for a truncated stat")
+}
+
+// writeProc writes a fake /proc entry for pid with the given CPU ticks, and
+// I/O counters growing along with them.
+func writeProc(tb testing.TB, procfs string, pid int, startTime, cpuTicks uint64) {
+	dir := filepath.Join(procfs, fmt.Sprint(pid))
+	utils.Raises(tb, os.MkdirAll(dir, 0755))
+
+	stat := fmt.Sprintf("%d (sh) R 1 %d 0 0 %d 0 0 0 0 0 %d 0 0 0 20 0 1 0 %d 4096 2\n", pid, pid, pid, cpuTicks, startTime)
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "status"), []byte("Name:\tsh\nUid:\t1000\t1000\t1000\t1000\nVmHWM:\t      12 kB\n"), 0644))
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte("sh\x00-c\x00true\x00"), 0644))
+	io := fmt.Sprintf("rchar: %d\nwchar: %d\nsyscr: %d\nsyscw: %d\nread_bytes: %d\nwrite_bytes: %d\ncancelled_write_bytes: 0\n",
+		cpuTicks*200, cpuTicks*20, cpuTicks, cpuTicks*2, cpuTicks*100, cpuTicks*10)
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "io"), []byte(io), 0644))
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "cgroup"), []byte("0::/user.slice/session-1.scope\n"), 0644))
+}
+
+func TestSnapshotCPUIsPerInterval(t *testing.T) {
+	procfs := t.TempDir()
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "stat"), []byte("cpu  1 2 3 4\nbtime 1000\n"), 0644))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "meminfo"), []byte("MemTotal:       16 kB\n"), 0644))
+
+	now := time.Unix(1100, 0)
+	s := &Sampler{procfs: procfs, now: func() time.Time { return now }, usernames: make(map[int32]string)}
+
+	// started 10s after boot and used 9s of CPU in its 90s lifetime.
+	writeProc(t, procfs, 7, 1000, 900)
+	snapshots, err := s.Snapshot()
+	utils.Raises(t, err)
+	utils.Equals(t, 1, len(snapshots))
+	utils.Equals(t, int64(1010000), snapshots[0].CreateTime)
+	utils.Equals(t, 10.0, snapshots[0].CPUPercent)
+	utils.Equals(t, int32(1000), snapshots[0].UID)
+	utils.Equals(t, uint64(12<<10), snapshots[0].PeakRSS)
+	utils.Equals(t, 9*time.Second, snapshots[0].CPUTime)
+	utils.Equals(t, "sh -c true", snapshots[0].Cmdline)
+	utils.Equals(t, IOStat{RChar: 180000, WChar: 18000, SyscR: 900, SyscW: 1800, ReadBytes: 90000, WriteBytes: 9000}, snapshots[0].IO)
+	utils.Equals(t, 1000.0, snapshots[0].IORate.ReadBytes)
+	utils.Assert(t, snapshots[0].IOReadable, "expected the I/O counters to be readable")
+	utils.Equals(t, "/user.slice/session-1.scope", snapshots[0].Cgroup)
+	utils.Equals(t, true, snapshots[0].Foreground)
+
+	// it then kept a CPU busy for half of the last 2s.
+	now = now.Add(2 * time.Second)
+	writeProc(t, procfs, 7, 1000, 1000)
+	snapshots, err = s.Snapshot()
+	utils.Raises(t, err)
+	utils.Equals(t, 50.0, snapshots[0].CPUPercent)
+	utils.Equals(t, IORate{RChar: 10000, WChar: 1000, SyscR: 50, SyscW: 100, ReadBytes: 5000, WriteBytes: 500}, snapshots[0].IORate)
+
+	// the PID was reused by a process started 1s ago.
+	now = now.Add(time.Second)
+	writeProc(t, procfs, 7, 10200, 25)
+	snapshots, err = s.Snapshot()
+	utils.Raises(t, err)
+	utils.Equals(t, 25.0, snapshots[0].CPUPercent)
+	utils.Equals(t, 2500.0, snapshots[0].IORate.ReadBytes)
+}
+
+func TestSnapshotUnreadableIO(t *testing.T) {
+	procfs := t.TempDir()
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "stat"), []byte("cpu  1 2 3 4\nbtime 1000\n"), 0644))
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "meminfo"), []byte("MemTotal:       16 kB\n"), 0644))
+
+	now := time.Unix(1100, 0)
+	s := &Sampler{procfs: procfs, now: func() time.Time { return now }, usernames: make(map[int32]string)}
+
+	// the I/O counters of processes of other users are not readable.
+	writeProc(t, procfs, 7, 1000, 900)
+	utils.Raises(t, os.Remove(filepath.Join(procfs, "7", "io")))
+	snapshots, err := s.Snapshot()
+	utils.Raises(t, err)
+	utils.Assert(t, !snapshots[0].IOReadable, "expected the I/O counters not to be readable")
+
+	// once readable, the counters have no earlier reading to measure a
+	// rate from.
+	now = now.Add(2 * time.Second)
+	writeProc(t, procfs, 7, 1000, 1000)
+	snapshots, err = s.Snapshot()
+	utils.Raises(t, err)
+	utils.Assert(t, snapshots[0].IOReadable, "expected the I/O counters to be readable")
+	utils.Equals(t, IORate{}, snapshots[0].IORate)
+}
+
+func TestReadCreateTime(t *testing.T) {
+	procfs := t.TempDir()
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "stat"), []byte("cpu  1 2 3 4\nbtime 1000\n"), 0644))
+
+	// started 10s after boot, as seen in a snapshot.
+	writeProc(t, procfs, 7, 1000, 900)
+	createTime, err := readCreateTime(procfs, 7)
+	utils.Raises(t, err)
+	utils.Equals(t, int64(1010000), createTime)
+
+	_, err = readCreateTime(procfs, 8)
+	utils.Assert(t, err != nil, "expected an error for a process that does not exist")
+}
+
+func TestFullName(t *testing.T) {
+	dir := t.TempDir()
+
+	// names shorter than the kernel limit are complete.
+	utils.Equals(t, "sh", fullName(dir, "sh", "/bin/sh"))
+
+	// a cut name is completed from the first argument, or from the
+	// executable when there are no arguments.
+	utils.Equals(t, "systemd-networkd", fullName(dir, "systemd-network", "/usr/lib/systemd/systemd-networkd"))
+	utils.Equals(t, "systemd-network", fullName(dir, "systemd-network", ""))
+	utils.Raises(t, os.Symlink("/usr/lib/systemd/systemd-networkd-wait-online (deleted)", filepath.Join(dir, "exe")))
+	utils.Equals(t, "systemd-networkd-wait-online", fullName(dir, "systemd-network", ""))
+
+	// names that do not continue the command name are not used.
+	utils.Equals(t, "kworker/u16:3-e", fullName(dir, "kworker/u16:3-e", ""))
+	utils.Equals(t, "systemd-network", fullName(dir, "systemd-network", "/bin/sh"))
+}
+
+func TestReadCgroup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cgroup")
+
+	// cgroup v1 only
+	utils.Raises(t, ioutil.WriteFile(path, []byte("12:pids:/system.slice/sshd.service\n11:cpu,cpuacct:/system.slice\n"), 0644))
+	cgroup, err := readCgroup(path)
+	utils.Raises(t, err)
+	utils.Equals(t, "/system.slice/sshd.service", cgroup)
+
+	// hybrid, the unified hierarchy wins
+	utils.Raises(t, ioutil.WriteFile(path, []byte("1:name=systemd:/\n0::/init.scope\n"), 0644))
+	cgroup, err = readCgroup(path)
+	utils.Raises(t, err)
+	utils.Equals(t, "/init.scope", cgroup)
+}
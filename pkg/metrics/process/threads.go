@@ -0,0 +1,151 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Thread holds the state of a thread of a process read from
+// /proc/<pid>/task/<tid>.
+type Thread struct {
+	Name                string
+	State               string
+	CPUPercent          float64 // share of a single CPU since the previous sample.
+	VoluntarySwitches   int64
+	InvoluntarySwitches int64
+	TID                 int32
+	Processor           int32 // CPU the thread last ran on, -1 if unknown.
+}
+
+// threadSampler samples the threads of a process, keeping the CPU times of
+// the previous sample to compute CPU usage per interval.
+type threadSampler struct {
+	procfs   string
+	taskDir  string
+	now      func() time.Time
+	bootTime time.Time
+	prev     map[Key]uint64
+	prevTime time.Time
+}
+
+func newThreadSampler(procfs string, pid int32) *threadSampler {
+	return &threadSampler{
+		procfs:  procfs,
+		taskDir: filepath.Join(procfs, strconv.Itoa(int(pid)), "task"),
+		now:     time.Now,
+	}
+}
+
+// sample returns the threads of the process ordered by TID. Threads that
+// exit while being read are skipped.
+func (s *threadSampler) sample() ([]Thread, error) {
+	if s.bootTime.IsZero() {
+		bootTime, err := readBootTime(s.procfs)
+		if err != nil {
+			return nil, err
+		}
+		s.bootTime = bootTime
+	}
+
+	entries, err := ioutil.ReadDir(s.taskDir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cur := make(map[Key]uint64, len(entries))
+	threads := make([]Thread, 0, len(entries))
+	for _, entry := range entries {
+		tid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		dir := filepath.Join(s.taskDir, entry.Name())
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		stat, err := parseStat(contents)
+		if err != nil {
+			continue
+		}
+		voluntary, involuntary, err := readCtxSwitches(filepath.Join(dir, "status"))
+		if err != nil {
+			continue
+		}
+
+		key := newKey(int32(tid), s.bootTime, stat.startTime)
+		cur[key] = stat.cpuTicks
+
+		// threads seen for the first time have no usage yet.
+		cpu := 0.0
+		if ticks, ok := s.prev[key]; ok {
+			cpu = cpuPercent(ticks, stat.cpuTicks, now.Sub(s.prevTime))
+		}
+
+		threads = append(threads, Thread{
+			TID:                 int32(tid),
+			Name:                stat.name,
+			State:               stat.state,
+			CPUPercent:          cpu,
+			Processor:           stat.processor,
+			VoluntarySwitches:   voluntary,
+			InvoluntarySwitches: involuntary,
+		})
+	}
+
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].TID < threads[j].TID
+	})
+
+	s.prev = cur
+	s.prevTime = now
+	return threads, nil
+}
+
+// readCtxSwitches returns the number of voluntary and involuntary context
+// switches from /proc/<pid>/task/<tid>/status.
+func readCtxSwitches(path string) (voluntary, involuntary int64, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "voluntary_ctxt_switches:":
+			voluntary, err = strconv.ParseInt(fields[1], 10, 64)
+		case "nonvoluntary_ctxt_switches:":
+			involuntary, err = strconv.ParseInt(fields[1], 10, 64)
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return voluntary, involuntary, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+// writeTask writes a fake /proc/<pid>/task/<tid> entry.
+func writeTask(tb testing.TB, taskDir string, tid int, cpuTicks uint64, processor int) {
+	dir := filepath.Join(taskDir, fmt.Sprint(tid))
+	utils.Raises(tb, os.MkdirAll(dir, 0755))
+
+	stat := fmt.Sprintf("%d (worker %d) R 1 1 0 0 -1 0 0 0 0 0 %d 0 0 0 20 0 4 0 100 4096 2 0 0 0 0 0 0 0 0 0 0 0 0 0 17 %d 0 0\n", tid, tid, cpuTicks, processor)
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+	status := fmt.Sprintf("Name:\tworker\nvoluntary_ctxt_switches:\t%d\nnonvoluntary_ctxt_switches:\t3\n", tid*10)
+	utils.Raises(tb, ioutil.WriteFile(filepath.Join(dir, "status"), []byte(status), 0644))
+}
+
+func TestThreadSampler(t *testing.T) {
+	procfs := t.TempDir()
+	taskDir := filepath.Join(procfs, "7", "task")
+	utils.Raises(t, ioutil.WriteFile(filepath.Join(procfs, "stat"), []byte("cpu  1 2 3 4\nbtime 1000\n"), 0644))
+
+	now := time.Unix(1000, 0)
+	s := newThreadSampler(procfs, 7)
+	s.now = func() time.Time { return now }
+
+	writeTask(t, taskDir, 7, 100, 0)
+	writeTask(t, taskDir, 9, 50, 3)
+	threads, err := s.sample()
+	utils.Raises(t, err)
+	utils.Equals(t, 2, len(threads))
+	utils.Equals(t, "worker 9", threads[1].Name)
+	utils.Equals(t, int32(3), threads[1].Processor)
+	utils.Equals(t, int64(90), threads[1].VoluntarySwitches)
+	utils.Equals(t, int64(3), threads[1].InvoluntarySwitches)
+	utils.Equals(t, 0.0, threads[1].CPUPercent)
+
+	// thread 9 spun for the whole second while thread 7 slept.
+	now = now.Add(time.Second)
+	writeTask(t, taskDir, 9, 150, 3)
+	threads, err = s.sample()
+	utils.Raises(t, err)
+	utils.Equals(t, 0.0, threads[0].CPUPercent)
+	utils.Equals(t, 100.0, threads[1].CPUPercent)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonl
+
+import (
+	"context"
+	"io"
+
+	"github.com/pesos/grofer/pkg/metrics/container"
+)
+
+// OverallContainerMetrics writes every sample of overall container metrics
+// received on the data channel to out.
+func OverallContainerMetrics(ctx context.Context, dataChannel chan container.OverallMetrics, out io.Writer) error {
+	w := newWriter(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			if err := w.write("containers", data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// PerContainerMetrics writes every sample of metrics for a single container
+// received on the data channel to out.
+func PerContainerMetrics(ctx context.Context, dataChannel chan container.PerContainerMetrics, out io.Writer) error {
+	w := newWriter(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			if err := w.write("container", data); err != nil {
+				return err
+			}
+		}
+	}
+}
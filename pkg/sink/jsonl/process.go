@@ -0,0 +1,216 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonl
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	proc "github.com/shirou/gopsutil/process"
+)
+
+// procEntry holds the values written for a process in the list of all
+// processes. It carries the same fields as the `grofer proc` table.
+type procEntry struct {
+	IORate     *process.IORate `json:"ioRate,omitempty"` // nil if /proc/<pid>/io is not readable.
+	Command    string          `json:"command"`
+	Status     string          `json:"status"`
+	CPU        float64         `json:"cpu"`
+	CreateTime int64           `json:"createTime"`
+	Mem        float32         `json:"mem"`
+	PID        int32           `json:"pid"`
+	NumThreads int32           `json:"numThreads"`
+	Foreground bool            `json:"foreground"`
+}
+
+// procEventEntry holds the values written for a process that started or
+// exited. Durations are in seconds.
+type procEventEntry struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	Command    string    `json:"command"`
+	Cmdline    string    `json:"cmdline"`
+	User       string    `json:"user"`
+	ExitStatus string    `json:"exitStatus,omitempty"`
+	CreateTime int64     `json:"createTime"`
+	Lifetime   float64   `json:"lifetime"`
+	CPUTime    float64   `json:"cpuTime"`
+	PeakRSS    uint64    `json:"peakRSS"`
+	PID        int32     `json:"pid"`
+	PPID       int32     `json:"ppid"`
+}
+
+// perProcEntry holds the values written for a single process.
+type perProcEntry struct {
+	MemoryInfo     *proc.MemoryInfoStat     `json:"memoryInfo,omitempty"`
+	PageFault      *proc.PageFaultsStat     `json:"pageFaults,omitempty"`
+	NumCtxSwitches *proc.NumCtxSwitchesStat `json:"ctxSwitches,omitempty"`
+	IO             *process.IOStat          `json:"io,omitempty"`
+	IORate         process.IORate           `json:"ioRate"`
+	Name           string                   `json:"name"`
+	Exe            string                   `json:"exe"`
+	Status         string                   `json:"status"`
+	Children       []int32                  `json:"children"`
+	CPUAffinity    []int32                  `json:"cpuAffinity,omitempty"`
+	CreateTime     int64                    `json:"createTime"`
+	CPUPercent     float64                  `json:"cpu"`
+	PID            int32                    `json:"pid"`
+	Nice           int32                    `json:"nice"`
+	NumThreads     int32                    `json:"numThreads"`
+	MemoryPercent  float32                  `json:"mem"`
+	IsRunning      bool                     `json:"running"`
+	Foreground     bool                     `json:"foreground"`
+	Background     bool                     `json:"background"`
+}
+
+func getProcEntries(procs []process.Snapshot) []procEntry {
+	entries := make([]procEntry, 0, len(procs))
+	for _, p := range procs {
+		entry := procEntry{
+			PID:        p.PID,
+			Command:    p.Name,
+			CPU:        p.CPUPercent,
+			Mem:        p.MemoryPercent,
+			Status:     p.Status,
+			Foreground: p.Foreground,
+			CreateTime: p.CreateTime,
+			NumThreads: p.NumThreads,
+		}
+		if p.IOReadable {
+			ioRate := p.IORate
+			entry.IORate = &ioRate
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func getProcEventEntries(events []process.Event) []procEventEntry {
+	entries := make([]procEventEntry, 0, len(events))
+	for _, e := range events {
+		entries = append(entries, procEventEntry{
+			Time:       e.Time,
+			Event:      e.Kind.String(),
+			PID:        e.Process.PID,
+			PPID:       e.Process.PPID,
+			Command:    e.Process.Name,
+			Cmdline:    e.Process.Cmdline,
+			User:       e.Process.Username,
+			ExitStatus: e.ExitStatus,
+			CreateTime: e.Process.CreateTime,
+			Lifetime:   e.Lifetime.Seconds(),
+			CPUTime:    e.Process.CPUTime.Seconds(),
+			PeakRSS:    e.PeakRSS,
+		})
+	}
+	return entries
+}
+
+func getPerProcEntry(p *process.Process) perProcEntry {
+	children := make([]int32, 0, len(p.Children))
+	for _, child := range p.Children {
+		children = append(children, child.Pid)
+	}
+
+	return perProcEntry{
+		PID:            p.Proc.Pid,
+		Name:           p.Name,
+		Exe:            p.Exe,
+		Status:         p.Status,
+		Children:       children,
+		CPUAffinity:    p.CPUAffinity,
+		CreateTime:     p.CreateTime,
+		CPUPercent:     p.CPUPercent,
+		Nice:           p.Nice,
+		NumThreads:     p.NumThreads,
+		MemoryPercent:  p.MemoryPercent,
+		MemoryInfo:     p.MemoryInfo,
+		PageFault:      p.PageFault,
+		NumCtxSwitches: p.NumCtxSwitches,
+		IO:             p.IO,
+		IORate:         p.IORate,
+		IsRunning:      p.IsRunning,
+		Foreground:     p.Foreground,
+		Background:     p.Background,
+	}
+}
+
+// AllProcs writes every sample of the list of running processes received
+// on the data channel to out, followed by the processes that started or
+// exited since the previous sample if there are any.
+func AllProcs(ctx context.Context, dataChannel chan []process.Snapshot, events *process.EventLog, out io.Writer) error {
+	w := newWriter(out)
+	written := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			if err := w.write("procs", getProcEntries(data)); err != nil {
+				return err
+			}
+
+			// events are read from the log rather than the samples, so
+			// that none are missed when samples are dropped.
+			var newEvents []process.Event
+			newEvents, written = events.Since(written)
+			if len(newEvents) > 0 {
+				if err := w.write("procEvents", getProcEventEntries(newEvents)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// PerProc writes every sample of metrics for a single process received on
+// the data channel to out.
+func PerProc(ctx context.Context, dataChannel chan *process.Process, out io.Writer) error {
+	w := newWriter(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			if err := w.write("proc", getPerProcEntry(data)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ComparedProcs writes every sample of metrics for the processes being
+// compared received on the data channel to out.
+func ComparedProcs(ctx context.Context, dataChannel chan []*process.Process, out io.Writer) error {
+	w := newWriter(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			entries := make([]perProcEntry, 0, len(data))
+			for _, p := range data {
+				entries = append(entries, getPerProcEntry(p))
+			}
+			if err := w.write("comparedProcs", entries); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonl
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// record is a single line written by the JSON-lines sink.
+type record struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Metrics   interface{} `json:"metrics"`
+	Kind      string      `json:"kind"`
+}
+
+// writer encodes every sample it receives as one JSON object per line.
+type writer struct {
+	encoder *json.Encoder
+}
+
+// newWriter is a constructor for the writer type.
+func newWriter(out io.Writer) *writer {
+	return &writer{
+		encoder: json.NewEncoder(out),
+	}
+}
+
+// write encodes a sample of the given kind, stamped with the current time.
+func (w *writer) write(kind string, metrics interface{}) error {
+	return w.encoder.Encode(record{
+		Timestamp: time.Now(),
+		Kind:      kind,
+		Metrics:   metrics,
+	})
+}
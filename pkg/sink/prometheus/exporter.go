@@ -0,0 +1,300 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/container"
+	"github.com/pesos/grofer/pkg/metrics/general"
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+// procSample holds the values exposed for a single process.
+type procSample struct {
+	name       string
+	cpu        float64
+	rss        uint64
+	mem        float32
+	pid        int32
+	numThreads int32
+}
+
+// Exporter keeps the latest samples produced by the collectors and
+// exposes them over HTTP in the Prometheus text exposition format.
+// Exporter implements the http.Handler interface.
+type Exporter struct {
+	// system holds the latest system wide metrics keyed by their FieldSet.
+	system     map[string]general.AggregatedMetrics
+	containers *container.OverallMetrics
+	procs      []procSample
+	mu         sync.RWMutex
+}
+
+// NewExporter is a constructor for the Exporter type.
+func NewExporter() *Exporter {
+	return &Exporter{
+		system: make(map[string]general.AggregatedMetrics),
+	}
+}
+
+// ConsumeSystemWideMetrics stores every sample of system wide metrics
+// received on the data channel until the context is cancelled.
+func (e *Exporter) ConsumeSystemWideMetrics(ctx context.Context, dataChannel chan general.AggregatedMetrics) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			e.mu.Lock()
+			e.system[data.FieldSet] = data
+			e.mu.Unlock()
+		}
+	}
+}
+
+// ConsumeProcs stores every sample of the list of running processes
+// received on the data channel until the context is cancelled.
+func (e *Exporter) ConsumeProcs(ctx context.Context, dataChannel chan []process.Snapshot) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			samples := getProcSamples(data)
+			e.mu.Lock()
+			e.procs = samples
+			e.mu.Unlock()
+		}
+	}
+}
+
+// ConsumeContainerMetrics stores every sample of overall container metrics
+// received on the data channel until the context is cancelled.
+func (e *Exporter) ConsumeContainerMetrics(ctx context.Context, dataChannel chan container.OverallMetrics) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-dataChannel:
+			e.mu.Lock()
+			e.containers = &data
+			e.mu.Unlock()
+		}
+	}
+}
+
+func getProcSamples(procs []process.Snapshot) []procSample {
+	samples := make([]procSample, 0, len(procs))
+	for _, p := range procs {
+		samples = append(samples, procSample{
+			pid:        p.PID,
+			name:       p.Name,
+			cpu:        p.CPUPercent,
+			mem:        p.MemoryPercent,
+			numThreads: p.NumThreads,
+			rss:        p.RSS,
+		})
+	}
+	return samples
+}
+
+// ServeHTTP writes the latest samples in the Prometheus text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	ew := newExpositionWriter(&buf)
+
+	e.mu.RLock()
+	e.writeSystemWideMetrics(ew)
+	e.writeProcMetrics(ew)
+	e.writeContainerMetrics(ew)
+	e.mu.RUnlock()
+
+	if ew.err != nil {
+		http.Error(w, ew.err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func (e *Exporter) writeSystemWideMetrics(ew *expositionWriter) {
+	if data, ok := e.system["CPU"]; ok {
+		ew.family("grofer_cpu_usage_percent", "Utilization of a CPU core in percent.", gauge)
+		for i, rate := range data.CPUStats {
+			ew.sample(rate, label{"cpu", strconv.Itoa(i)})
+		}
+	}
+
+	if data, ok := e.system["MEM"]; ok && data.MemStats != nil {
+		memStats := data.MemStats
+		ew.family("grofer_memory_bytes", "Physical memory in bytes, by state.", gauge)
+		ew.sample(float64(memStats.Total), label{"state", "total"})
+		ew.sample(float64(memStats.Used), label{"state", "used"})
+		ew.sample(float64(memStats.Available), label{"state", "available"})
+		ew.sample(float64(memStats.Free), label{"state", "free"})
+		ew.sample(float64(memStats.Cached), label{"state", "cached"})
+	}
+
+	if data, ok := e.system["DISK"]; ok {
+		e.writeDiskMetrics(ew, data.DiskStats)
+	}
+
+	if data, ok := e.system["NET"]; ok {
+		nics := make([]string, 0, len(data.NetStats))
+		for nic := range data.NetStats {
+			nics = append(nics, nic)
+		}
+		sort.Strings(nics)
+
+		ew.family("grofer_network_transmit_bytes_total", "Bytes sent on a network interface.", counter)
+		for _, nic := range nics {
+			ew.sample(float64(data.NetStats[nic].BytesSent), label{"interface", nic})
+		}
+		ew.family("grofer_network_receive_bytes_total", "Bytes received on a network interface.", counter)
+		for _, nic := range nics {
+			ew.sample(float64(data.NetStats[nic].BytesRecv), label{"interface", nic})
+		}
+	}
+
+	if data, ok := e.system["TEMP"]; ok {
+		ew.family("grofer_temperature_celsius", "Temperature reported by a sensor in degree Celsius.", gauge)
+		for _, t := range data.TempStats {
+			ew.sample(t.Celsius, label{"sensor", t.Sensor})
+		}
+	}
+
+	if data, ok := e.system["BATTERY"]; ok {
+		ew.family("grofer_battery_percent", "Remaining battery charge in percent.", gauge)
+		ew.sample(data.BatteryPercent)
+	}
+}
+
+func (e *Exporter) writeDiskMetrics(ew *expositionWriter, diskStats []general.DiskStats) {
+	families := []struct {
+		value func(general.DiskStats) float64
+		name  string
+		help  string
+	}{
+		{func(d general.DiskStats) float64 { return float64(d.Total) }, "grofer_filesystem_size_bytes", "Size of a filesystem in bytes."},
+		{func(d general.DiskStats) float64 { return float64(d.Used) }, "grofer_filesystem_used_bytes", "Used space on a filesystem in bytes."},
+		{func(d general.DiskStats) float64 { return float64(d.Free) }, "grofer_filesystem_free_bytes", "Free space on a filesystem in bytes."},
+		{func(d general.DiskStats) float64 { return d.UsedPercent }, "grofer_filesystem_used_percent", "Used space on a filesystem in percent."},
+	}
+
+	for _, f := range families {
+		ew.family(f.name, f.help, gauge)
+		for _, d := range diskStats {
+			ew.sample(f.value(d), label{"mountpoint", d.Mountpoint}, label{"fstype", d.Fstype})
+		}
+	}
+}
+
+func (e *Exporter) writeProcMetrics(ew *expositionWriter) {
+	if e.procs == nil {
+		return
+	}
+
+	families := []struct {
+		value func(procSample) float64
+		name  string
+		help  string
+	}{
+		{func(p procSample) float64 { return p.cpu }, "grofer_process_cpu_percent", "CPU utilization of a process in percent."},
+		{func(p procSample) float64 { return float64(p.mem) }, "grofer_process_memory_percent", "Share of physical memory used by a process in percent."},
+		{func(p procSample) float64 { return float64(p.rss) }, "grofer_process_resident_memory_bytes", "Resident set size of a process in bytes."},
+		{func(p procSample) float64 { return float64(p.numThreads) }, "grofer_process_threads", "Number of threads in a process."},
+	}
+
+	ew.family("grofer_processes", "Number of running processes.", gauge)
+	ew.sample(float64(len(e.procs)))
+
+	for _, f := range families {
+		ew.family(f.name, f.help, gauge)
+		for _, p := range e.procs {
+			ew.sample(f.value(p), label{"pid", strconv.Itoa(int(p.pid))}, label{"name", p.name})
+		}
+	}
+}
+
+func (e *Exporter) writeContainerMetrics(ew *expositionWriter) {
+	if e.containers == nil {
+		return
+	}
+
+	families := []struct {
+		value func(container.PerContainerMetrics) float64
+		name  string
+		help  string
+		typ   metricType
+	}{
+		{func(c container.PerContainerMetrics) float64 { return c.CPU }, "grofer_container_cpu_percent", "CPU utilization of a container in percent.", gauge},
+		{func(c container.PerContainerMetrics) float64 { return c.Mem }, "grofer_container_memory_percent", "Memory used by a container in percent of its limit.", gauge},
+		{func(c container.PerContainerMetrics) float64 { return c.Net.Rx }, "grofer_container_network_receive_bytes_total", "Bytes received by a container.", counter},
+		{func(c container.PerContainerMetrics) float64 { return c.Net.Tx }, "grofer_container_network_transmit_bytes_total", "Bytes sent by a container.", counter},
+		{func(c container.PerContainerMetrics) float64 { return float64(c.Blk.Read) }, "grofer_container_block_read_bytes_total", "Bytes read from block devices by a container.", counter},
+		{func(c container.PerContainerMetrics) float64 { return float64(c.Blk.Write) }, "grofer_container_block_write_bytes_total", "Bytes written to block devices by a container.", counter},
+	}
+
+	ew.family("grofer_containers", "Number of listed containers.", gauge)
+	ew.sample(float64(len(e.containers.PerContainer)))
+
+	for _, f := range families {
+		ew.family(f.name, f.help, f.typ)
+		for _, c := range e.containers.PerContainer {
+			ew.sample(f.value(c),
+				label{"name", c.Name},
+				label{"id", c.ID},
+				label{"image", c.Image},
+			)
+		}
+	}
+}
+
+// ListenAndServe exposes the Exporter at /metrics on the given address
+// until the context is cancelled.
+func ListenAndServe(ctx context.Context, address string, e *Exporter) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+
+	server := &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+		return ctx.Err()
+	}
+}
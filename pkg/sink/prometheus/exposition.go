@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metricType is the TYPE of a metric family in the text exposition format.
+type metricType string
+
+const (
+	gauge   metricType = "gauge"
+	counter metricType = "counter"
+)
+
+// label is a single name/value pair attached to a sample.
+type label struct {
+	name  string
+	value string
+}
+
+// expositionWriter writes metric families in the Prometheus text
+// exposition format (version 0.0.4).
+type expositionWriter struct {
+	out  io.Writer
+	err  error
+	name string
+}
+
+func newExpositionWriter(out io.Writer) *expositionWriter {
+	return &expositionWriter{out: out}
+}
+
+// family starts a new metric family by writing its HELP and TYPE lines.
+// Subsequent calls to sample belong to this family.
+func (ew *expositionWriter) family(name, help string, typ metricType) {
+	ew.name = name
+	ew.printf("# HELP %s %s\n", name, escapeHelp(help))
+	ew.printf("# TYPE %s %s\n", name, typ)
+}
+
+// sample writes a single sample of the current metric family.
+func (ew *expositionWriter) sample(value float64, labels ...label) {
+	ew.printf("%s%s %s\n", ew.name, formatLabels(labels), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (ew *expositionWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.out, format, args...)
+}
+
+func formatLabels(labels []label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, l.name+"=\""+escapeLabelValue(l.value)+"\"")
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
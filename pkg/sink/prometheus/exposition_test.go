@@ -0,0 +1,72 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/general"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestExpositionWriter(t *testing.T) {
+	var buf bytes.Buffer
+	ew := newExpositionWriter(&buf)
+
+	ew.family("grofer_test_bytes", "A test\\metric.\nSecond line.", gauge)
+	ew.sample(1024, label{"name", `quoted "value"`}, label{"path", `C:\tmp`})
+	ew.family("grofer_test_total", "A counter.", counter)
+	ew.sample(0.5)
+
+	expected := `# HELP grofer_test_bytes A test\\metric.\nSecond line.
+# TYPE grofer_test_bytes gauge
+grofer_test_bytes{name="quoted \"value\"",path="C:\\tmp"} 1024
+# HELP grofer_test_total A counter.
+# TYPE grofer_test_total counter
+grofer_test_total 0.5
+`
+
+	utils.Raises(t, ew.err)
+	utils.Equals(t, expected, buf.String())
+}
+
+func TestExporterSystemWideMetrics(t *testing.T) {
+	e := NewExporter()
+	e.system["MEM"] = general.AggregatedMetrics{
+		FieldSet: "MEM",
+		MemStats: &general.MemoryStats{Total: 16777216000, Used: 1234567, Available: 2, Free: 3, Cached: 4},
+	}
+	e.system["TEMP"] = general.AggregatedMetrics{
+		FieldSet:  "TEMP",
+		TempStats: []general.TemperatureStats{{Sensor: "coretemp_core0", Celsius: 45.5}},
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, expected := range []string{
+		`grofer_memory_bytes{state="total"} 1.6777216e+10`,
+		`grofer_memory_bytes{state="used"} 1.234567e+06`,
+		`grofer_temperature_celsius{sensor="coretemp_core0"} 45.5`,
+	} {
+		utils.Assert(t, strings.Contains(body, expected), "missing sample %q in:\n%s", expected, body)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	containerMetrics "github.com/pesos/grofer/pkg/metrics/container"
+)
+
+// actionVerbs describe the actions of the action menu in error messages.
+var actionVerbs = map[string]string{
+	"PAUSE":   "pausing",
+	"UNPAUSE": "un-pausing",
+	"RESTART": "restarting",
+	"STOP":    "stopping",
+	"KILL":    "killing",
+	"REMOVE":  "removing",
+}
+
+// destructiveActions are the actions of the action menu that are only
+// performed once confirmed.
+var destructiveActions = map[string]bool{
+	"RESTART": true,
+	"STOP":    true,
+	"KILL":    true,
+	"REMOVE":  true,
+}
+
+// performAction performs an action of the action menu on a container and
+// waits for the container to reach the state that the action leads to.
+func performAction(ctx context.Context, cli *client.Client, cid, action string) error {
+	var err error
+	var state string
+
+	switch action {
+	case "PAUSE":
+		state = "paused"
+		err = cli.ContainerPause(ctx, cid)
+
+	case "UNPAUSE":
+		state = "running"
+		err = cli.ContainerUnpause(ctx, cid)
+
+	case "RESTART":
+		state = "running"
+		err = cli.ContainerRestart(ctx, cid, nil)
+
+	case "STOP":
+		state = "exited"
+		err = cli.ContainerStop(ctx, cid, nil)
+
+	case "KILL":
+		state = "exited"
+		err = cli.ContainerKill(ctx, cid, "")
+
+	case "REMOVE":
+		err = cli.ContainerRemove(ctx, cid, types.ContainerRemoveOptions{
+			RemoveVolumes: true,
+			Force:         true,
+		})
+		if err == nil {
+			// a removed container can no longer be inspected
+			err = containerMetrics.Wait(ctx, cli, cid, "removed")
+			if client.IsErrNotFound(err) {
+				err = nil
+			}
+		}
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+	return containerMetrics.Wait(ctx, cli, cid, state)
+}
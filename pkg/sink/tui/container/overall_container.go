@@ -23,7 +23,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	ui "github.com/gizak/termui/v3"
 	"github.com/pesos/grofer/pkg/core"
@@ -34,8 +33,9 @@ import (
 	viz "github.com/pesos/grofer/pkg/utils/visualization"
 )
 
-// OverallVisuals provides the UI for overall container metrics
-func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChannel chan containerMetrics.OverallMetrics, refreshRate uint64) error {
+// OverallVisuals provides the UI for overall container metrics, hiding the
+// container actions if readOnly is set.
+func OverallVisuals(ctx context.Context, cli *client.Client, all, readOnly bool, dataChannel chan containerMetrics.OverallMetrics, refreshRate uint64) error {
 	if err := ui.Init(); err != nil {
 		return err
 	}
@@ -48,6 +48,10 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 	var help *misc.HelpMenu = misc.NewHelpMenu().ForCommand(misc.ContainerCommand)
 	var errorBox *misc.ErrorBox = misc.NewErrorBox()
 	var actions *misc.ActionTable = misc.NewActionTable()
+	var confirmBox *misc.ConfirmBox = misc.NewConfirmBox()
+	if readOnly {
+		help = help.ForCommand(misc.ReadOnlyContainerCommand)
+	}
 
 	// Create new page and select table
 	page := newOverallContainerPage()
@@ -109,6 +113,11 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 			errorBox.Resize(w, h)
 			ui.Render(errorBox)
 
+		case core.Confirm:
+			ui.Render(page.Grid)
+			confirmBox.Resize(w, h)
+			ui.Render(confirmBox)
+
 		case core.Action:
 			page.DetailsTable.CursorColor = actionStyle
 			actions.SetRect(0, 0, w/6, h)
@@ -122,6 +131,34 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 		}
 	}
 
+	// IDs of the containers marked for a bulk action
+	marked := make(map[string]bool)
+
+	// highlights the marked containers, forgetting those that are gone
+	markRows := func() {
+		page.DetailsTable.MarkedRows = make(map[int]bool)
+		present := make(map[string]bool)
+		for i, row := range page.DetailsTable.Rows {
+			present[row[0]] = true
+			if marked[row[0]] {
+				page.DetailsTable.MarkedRows[i] = true
+			}
+		}
+		for id := range marked {
+			if !present[id] {
+				delete(marked, id)
+			}
+		}
+
+		page.DetailsTable.Title = " Details "
+		if readOnly {
+			page.DetailsTable.Title = " Details | Read-only "
+		}
+		if len(marked) > 0 {
+			page.DetailsTable.Title = fmt.Sprintf(" Details | Marked: %d ", len(marked))
+		}
+	}
+
 	updateDetails := func(data containerMetrics.OverallMetrics) {
 		// update cpu %
 		page.CPUChart.Percent = int(data.TotalCPU)
@@ -165,6 +202,46 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 		if sortIdx != -1 {
 			utils.SortData(page.DetailsTable.Rows, sortIdx, sortAsc, "CONTAINER")
 		}
+		markRows()
+	}
+
+	// the action waiting for confirmation
+	var confirmed func()
+
+	// performs an action on the given containers, showing a failure in the
+	// error box, or the outcome for every container if several were marked
+	performActions := func(actionSelected string, cids []string) {
+		results := make([]misc.ActionResult, 0, len(cids))
+		for _, id := range cids {
+			err := performAction(ctx, cli, id, actionSelected)
+			results = append(results, misc.ActionResult{Target: id, Err: err})
+		}
+
+		// Flush out stale data
+		<-dataChannel
+		data, _ := containerMetrics.GetOverallMetrics(ctx, cli, all)
+		updateDetails(data)
+
+		// Display error box if action failed/timed out, or the
+		// outcome for every container if several were marked
+		scrollableWidget.DisableCursor()
+		if len(cids) > 1 {
+			errorBox.SetResults(actionSelected, results)
+			utilitySelected = core.Error
+			scrollableWidget = errorBox.Table
+		} else if results[0].Err != nil {
+			errorBox.SetErrorString(fmt.Sprintf("Error %s container with ID: %s", actionVerbs[actionSelected], cids[0]), results[0].Err)
+			utilitySelected = core.Error
+			scrollableWidget = errorBox.Table
+		} else {
+			utilitySelected = core.None
+			scrollableWidget = page.DetailsTable
+		}
+		scrollableWidget.EnableCursor()
+
+		updateUI()
+
+		runProc = true
 	}
 
 	updateUI() // Initialize empty UI
@@ -179,6 +256,24 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 			return ctx.Err()
 		case e := <-uiEvents:
 
+			// while a confirmation is asked for only its answer is handled
+			if utilitySelected == core.Confirm {
+				switch e.ID {
+				case "<C-c>":
+					return core.ErrCanceledByUser
+				case "y":
+					confirmed()
+				case "n", "<Escape>":
+					utilitySelected = core.None
+					scrollableWidget.DisableCursor()
+					scrollableWidget = page.DetailsTable
+					scrollableWidget.EnableCursor()
+					runProc = true
+				}
+				updateUI()
+				continue
+			}
+
 			switch e.ID {
 			case "q", "<C-c>":
 				return core.ErrCanceledByUser
@@ -235,7 +330,7 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 
 			// Container Action Selction
 			case "<Enter>":
-				if utilitySelected == core.None {
+				if utilitySelected == core.None && !readOnly {
 					if page.DetailsTable.SelectedRow < len(page.DetailsTable.Rows) {
 						// get CID from the data
 						cid = page.DetailsTable.Rows[page.DetailsTable.SelectedRow][0]
@@ -248,90 +343,68 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 						scrollableWidget.EnableCursor()
 					}
 				} else if utilitySelected == core.Action {
-					var err error
-
 					actionSelected := actions.SelectedAction()
 
-					switch actionSelected {
-					// Pause Action
-					case "PAUSE":
-						err = cli.ContainerPause(ctx, cid)
-						if err == nil {
-							err = containerMetrics.Wait(ctx, cli, cid, "paused")
-						} else {
-							errorBox.SetErrorString(fmt.Sprintf("Error pausing container with ID: %s", cid), err)
+					// perform the action on the marked containers if any
+					cids := []string{}
+					for _, row := range page.DetailsTable.Rows {
+						if marked[row[0]] {
+							cids = append(cids, row[0])
 						}
+					}
+					if len(cids) == 0 {
+						cids = append(cids, cid)
+					}
 
-					// Unpause Action
-					case "UNPAUSE":
-						err = cli.ContainerUnpause(ctx, cid)
-						if err == nil {
-							err = containerMetrics.Wait(ctx, cli, cid, "running")
-						} else {
-							errorBox.SetErrorString(fmt.Sprintf("Error un-pausing container with ID: %s", cid), err)
+					if destructiveActions[actionSelected] {
+						// ask for confirmation, naming the containers
+						names := make(map[string]string, len(page.DetailsTable.Rows))
+						for _, row := range page.DetailsTable.Rows {
+							names[row[0]] = row[2]
 						}
-
-					// Restart Action
-					case "RESTART":
-						err = cli.ContainerRestart(ctx, cid, nil)
-						if err == nil {
-							err = containerMetrics.Wait(ctx, cli, cid, "running")
-						} else {
-							errorBox.SetErrorString(fmt.Sprintf("Error restarting container with ID: %s", cid), err)
+						targets := make([]string, 0, len(cids))
+						for _, id := range cids {
+							targets = append(targets, fmt.Sprintf("%s (%s)", id, names[id]))
 						}
-
-					// Stop action
-					case "STOP":
-						err = cli.ContainerStop(ctx, cid, nil)
-						if err == nil {
-							err = containerMetrics.Wait(ctx, cli, cid, "exited")
-						} else {
-							errorBox.SetErrorString(fmt.Sprintf("Error stopping container with ID: %s", cid), err)
+						question := fmt.Sprintf("%s this container?", actionSelected)
+						if len(cids) > 1 {
+							question = fmt.Sprintf("%s these %d containers?", actionSelected, len(cids))
 						}
-
-					// Kill action
-					case "KILL":
-						err = cli.ContainerKill(ctx, cid, "")
-						if err == nil {
-							err = containerMetrics.Wait(ctx, cli, cid, "exited")
-						} else {
-							errorBox.SetErrorString(fmt.Sprintf("Error killing container with ID: %s", cid), err)
-						}
-
-					// Remove action
-					case "REMOVE":
-						err = cli.ContainerRemove(ctx, cid, types.ContainerRemoveOptions{
-							RemoveVolumes: true,
-							Force:         true,
-						})
-						if err == nil {
-							err = containerMetrics.Wait(ctx, cli, cid, "removed")
-						} else {
-							errorBox.SetErrorString(fmt.Sprintf("Error removing container with ID: %s", cid), err)
+						confirmBox.SetQuestion(question, targets)
+						confirmed = func() {
+							performActions(actionSelected, cids)
 						}
+						utilitySelected = core.Confirm
+					} else {
+						performActions(actionSelected, cids)
 					}
+				}
 
-					// Flush out stale data
-					<-dataChannel
-					data, _ := containerMetrics.GetOverallMetrics(ctx, cli, all)
-					updateDetails(data)
-
-					// Display error box if action failed/timed out
-					if err != nil {
-						errorBox.SetErrorString("Timeout Error", err)
-						utilitySelected = core.Error
-						scrollableWidget.DisableCursor()
-						scrollableWidget = errorBox.Table
+			// handle marking of containers for bulk actions
+			case "<Space>":
+				if utilitySelected == core.None && !readOnly && page.DetailsTable.SelectedRow < len(page.DetailsTable.Rows) {
+					id := page.DetailsTable.Rows[page.DetailsTable.SelectedRow][0]
+					if marked[id] {
+						delete(marked, id)
 					} else {
-						utilitySelected = core.None
-						scrollableWidget.DisableCursor()
-						scrollableWidget = page.DetailsTable
-						scrollableWidget.EnableCursor()
+						marked[id] = true
 					}
+					markRows()
+					page.DetailsTable.ScrollDown()
+				}
 
-					updateUI()
+			case "A":
+				if utilitySelected == core.None && !readOnly {
+					for _, row := range page.DetailsTable.Rows {
+						marked[row[0]] = true
+					}
+					markRows()
+				}
 
-					runProc = true
+			case "U":
+				if utilitySelected == core.None {
+					marked = make(map[string]bool)
+					markRows()
 				}
 
 			// Handle sorting
@@ -345,6 +418,7 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 					page.DetailsTable.Header[sortIdx] = header[sortIdx] + " " + viz.UpArrow
 					sortAsc = true
 					utils.SortData(page.DetailsTable.Rows, sortIdx, sortAsc, "CONTAINER")
+					markRows()
 				}
 
 			// Sort Descending
@@ -356,6 +430,7 @@ func OverallVisuals(ctx context.Context, cli *client.Client, all bool, dataChann
 					page.DetailsTable.Header[sortIdx] = header[sortIdx] + " " + viz.DownArrow
 					sortAsc = false
 					utils.SortData(page.DetailsTable.Rows, sortIdx, sortAsc, "CONTAINER")
+					markRows()
 				}
 
 			// Disable Sort
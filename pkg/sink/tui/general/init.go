@@ -21,6 +21,7 @@ import (
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
+	"github.com/pesos/grofer/pkg/metrics/general"
 	viz "github.com/pesos/grofer/pkg/utils/visualization"
 )
 
@@ -50,8 +51,28 @@ type CPUPage struct {
 	SoftChart   *widgets.Gauge
 	IdleChart   *widgets.Gauge
 	StealChart  *widgets.Gauge
-	CPUChart    *widgets.Table
-	CPUTable    *viz.Table
+	// CoreChart stacks the time every core spends in
+	// each state, colored like the gauge of the state.
+	CoreChart *widgets.StackedBarChart
+	// CPUTable breaks down the time every core spends
+	// in each state on machines with many cores.
+	CPUTable *viz.Table
+}
+
+// coreStates are the states shown per core on the CPU page, in the order
+// they are stacked in, along with the color of their gauge and bar.
+var coreStates = []struct {
+	load  func(general.CoreLoad) float64
+	name  string
+	color ui.Color
+}{
+	{func(cl general.CoreLoad) float64 { return cl.Usr }, "Usr", ui.ColorBlue},
+	{func(cl general.CoreLoad) float64 { return cl.Nice }, "Nice", ui.ColorCyan},
+	{func(cl general.CoreLoad) float64 { return cl.Sys }, "Sys", ui.ColorRed},
+	{func(cl general.CoreLoad) float64 { return cl.Iowait }, "Iowait", ui.ColorYellow},
+	{func(cl general.CoreLoad) float64 { return cl.Irq }, "Irq", ui.ColorMagenta},
+	{func(cl general.CoreLoad) float64 { return cl.Soft }, "Soft", ui.ColorGreen},
+	{func(cl general.CoreLoad) float64 { return cl.Steal }, "Steal", ui.ColorWhite},
 }
 
 // NewPage returns a new page initialized from the MainPage struct
@@ -88,7 +109,7 @@ func NewCPUPage(numCores int) *CPUPage {
 		SoftChart:   widgets.NewGauge(),
 		IdleChart:   widgets.NewGauge(),
 		StealChart:  widgets.NewGauge(),
-		CPUChart:    widgets.NewTable(),
+		CoreChart:   widgets.NewStackedBarChart(),
 		CPUTable:    viz.NewTable(),
 	}
 	page.init(numCores)
@@ -356,67 +377,85 @@ func (page *CPUPage) init(numCores int) {
 	page.StealChart.TitleStyle.Fg = ui.ColorClear
 	page.StealChart.LabelStyle.Fg = ui.ColorClear
 
-	page.CPUChart.Title = " CPU Usage "
-	page.CPUChart.TitleStyle = ui.NewStyle(ui.ColorClear)
-	page.CPUChart.BorderStyle = ui.NewStyle(ui.ColorCyan)
-	page.CPUChart.TextStyle = ui.NewStyle(ui.ColorClear)
-	page.CPUChart.TextAlignment = ui.AlignCenter
-	page.CPUChart.RowSeparator = true
-	page.CPUChart.ColumnResizer = func() {
-		columnWidths := []int{}
-		x := page.CPUChart.Inner.Dx()
-		for i := 0; i < numCores; i++ {
-			columnWidths = append(columnWidths, x/numCores)
-		}
+	// color every gauge like the bars of its state in the CoreChart.
+	gauges := map[string]*widgets.Gauge{
+		"Usr":    page.UsrChart,
+		"Nice":   page.NiceChart,
+		"Sys":    page.SysChart,
+		"Iowait": page.IowaitChart,
+		"Irq":    page.IrqChart,
+		"Soft":   page.SoftChart,
+		"Steal":  page.StealChart,
+	}
+	barColors := []ui.Color{}
+	for _, state := range coreStates {
+		gauges[state.name].BarColor = state.color
+		barColors = append(barColors, state.color)
+	}
 
-		page.CPUChart.ColumnWidths = columnWidths
+	page.CoreChart.Title = " Per Core Load "
+	page.CoreChart.TitleStyle = ui.NewStyle(ui.ColorClear)
+	page.CoreChart.BorderStyle = ui.NewStyle(ui.ColorCyan)
+	page.CoreChart.BarColors = barColors
+	page.CoreChart.MaxVal = 100
+	page.CoreChart.NumStyles = []ui.Style{ui.NewStyle(ui.ColorBlack)}
+	page.CoreChart.NumFormatter = func(n float64) string {
+		// only label states that are tall enough to read.
+		if n < 10 {
+			return ""
+		}
+		return fmt.Sprintf("%.0f", n)
 	}
 
 	page.CPUTable.Title = " CPU Usage "
 	page.CPUTable.TitleStyle = ui.NewStyle(ui.ColorClear)
 	page.CPUTable.BorderStyle = ui.NewStyle(ui.ColorCyan)
+	page.CPUTable.Header = []string{"CPU", "Usage"}
+	for _, state := range coreStates {
+		page.CPUTable.Header = append(page.CPUTable.Header, state.name)
+	}
 	page.CPUTable.ColResizer = func() {
 		x := page.CPUTable.Inner.Dx()
-
-		page.CPUTable.ColWidths = []int{x / 2, x / 2}
+		colWidths := []int{}
+		for range page.CPUTable.Header {
+			colWidths = append(colWidths, x/len(page.CPUTable.Header))
+		}
+		page.CPUTable.ColWidths = colWidths
 	}
-	page.CPUTable.Header = []string{"CPU", "Usage"}
 	page.CPUTable.ShowCursor = true
 	page.CPUTable.CursorColor = ui.ColorCyan
 
+	gaugeRows := []interface{}{
+		ui.NewRow(0.25,
+			ui.NewCol(0.5, page.UsrChart),
+			ui.NewCol(0.5, page.NiceChart),
+		),
+		ui.NewRow(0.25,
+			ui.NewCol(0.5, page.SysChart),
+			ui.NewCol(0.5, page.IowaitChart),
+		),
+		ui.NewRow(0.25,
+			ui.NewCol(0.5, page.IrqChart),
+			ui.NewCol(0.5, page.SoftChart),
+		),
+		ui.NewRow(0.25,
+			ui.NewCol(0.5, page.IdleChart),
+			ui.NewCol(0.5, page.StealChart),
+		),
+	}
+
 	if numCores > 8 {
 		page.Grid.Set(
-			ui.NewCol(0.3, page.CPUTable),
-			ui.NewCol(0.7,
-				ui.NewRow(0.125, page.UsrChart),
-				ui.NewRow(0.125, page.NiceChart),
-				ui.NewRow(0.125, page.SysChart),
-				ui.NewRow(0.125, page.IowaitChart),
-				ui.NewRow(0.125, page.IrqChart),
-				ui.NewRow(0.125, page.SoftChart),
-				ui.NewRow(0.125, page.IdleChart),
-				ui.NewRow(0.125, page.StealChart),
+			ui.NewCol(0.4, page.CPUTable),
+			ui.NewCol(0.6,
+				ui.NewRow(0.5, page.CoreChart),
+				ui.NewRow(0.5, gaugeRows...),
 			),
 		)
 	} else {
 		page.Grid.Set(
-			ui.NewRow(0.17,
-				ui.NewCol(0.5, page.UsrChart),
-				ui.NewCol(0.5, page.NiceChart),
-			),
-			ui.NewRow(0.17,
-				ui.NewCol(0.5, page.SysChart),
-				ui.NewCol(0.5, page.IowaitChart),
-			),
-			ui.NewRow(0.17,
-				ui.NewCol(0.5, page.IrqChart),
-				ui.NewCol(0.5, page.SoftChart),
-			),
-			ui.NewRow(0.17,
-				ui.NewCol(0.5, page.IdleChart),
-				ui.NewCol(0.5, page.StealChart),
-			),
-			ui.NewRow(0.30, page.CPUChart),
+			ui.NewRow(0.6, gaugeRows...),
+			ui.NewRow(0.4, page.CoreChart),
 		)
 	}
 
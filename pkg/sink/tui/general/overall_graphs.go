@@ -19,11 +19,14 @@ package general
 import (
 	"context"
 	"fmt"
+	"math"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
 	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
 	"github.com/pesos/grofer/pkg/core"
 	"github.com/pesos/grofer/pkg/metrics/general"
 	"github.com/pesos/grofer/pkg/sink/tui/misc"
@@ -43,8 +46,6 @@ func RenderCharts(ctx context.Context, dataChannel chan general.AggregatedMetric
 	defer ui.Close()
 
 	var on sync.Once
-	var totalBytesRecv float64
-	var totalBytesSent float64
 	var help *misc.HelpMenu = misc.NewHelpMenu().ForCommand(misc.RootCommand)
 
 	// Get number of cores in machine
@@ -179,14 +180,14 @@ func RenderCharts(ctx context.Context, dataChannel chan general.AggregatedMetric
 				switch data.FieldSet {
 
 				case "INFO": // Update Info table
-					header, rows := data.HostInfo[0], data.HostInfo[1:]
+					header, rows := getHostInfoRows(data.HostInfo)
 					page.InfoTable.Header = header
 					page.InfoTable.Rows = rows
 
 				case "BATTERY": // Update Battery Gauge
 					page.BatteryGauge.Title = " Battery % "
 
-					percent := data.BatteryPercent
+					percent := int(data.BatteryPercent)
 					page.BatteryGauge.Percent = percent
 					switch {
 					case percent < 33:
@@ -209,54 +210,43 @@ func RenderCharts(ctx context.Context, dataChannel chan general.AggregatedMetric
 					}
 
 				case "MEM": // Update Memory stats
-					page.MemoryChart.MaxVal = data.MemStats[0]
-					page.MemoryChart.Data = data.MemStats[1:]
-					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Used: %.2fG/%.2fG", data.MemStats[1], data.MemStats[0]))
-					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Available: %.2fG/%.2fG", data.MemStats[2], data.MemStats[0]))
-					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Free: %.2fG/%.2fG", data.MemStats[3], data.MemStats[0]))
-					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Cached: %.2fG/%.2fG", data.MemStats[4], data.MemStats[0]))
+					memStats := data.MemStats
+					total := roundOff(memStats.Total)
+					memRates := []float64{roundOff(memStats.Used), roundOff(memStats.Available), roundOff(memStats.Free), roundOff(memStats.Cached)}
+					page.MemoryChart.MaxVal = total
+					page.MemoryChart.Data = memRates
+					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Used: %.2fG/%.2fG", memRates[0], total))
+					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Available: %.2fG/%.2fG", memRates[1], total))
+					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Free: %.2fG/%.2fG", memRates[2], total))
+					page.MemoryChart.Labels = append(page.MemoryChart.Labels, fmt.Sprintf("Cached: %.2fG/%.2fG", memRates[3], total))
 
 				case "DISK": // Update Disk stats
-					page.DiskChart.Header = data.DiskStats[0]
-					page.DiskChart.Rows = data.DiskStats[1:]
+					page.DiskChart.Header = []string{"Mount", "Total", "Used %", "Used", "Free", "FS Type"}
+					page.DiskChart.Rows = getDiskRows(data.DiskStats)
 
 				case "TEMP":
-					page.TemperatureTable.Header = data.TempStats[0]
-					page.TemperatureTable.Rows = data.TempStats[1:]
+					page.TemperatureTable.Header = []string{"Sensor", "Temp(°C)"}
+					page.TemperatureTable.Rows = getTemperatureRows(data.TempStats)
 
 				case "NET": // Update Network stats
-					var curBytesRecv, curBytesSent float64
+					var totalBytesRecv, totalBytesSent float64
+					var recentBytesRecv, recentBytesSent float64
 
 					for _, netInterface := range data.NetStats {
-						curBytesRecv += netInterface[1]
-						curBytesSent += netInterface[0]
+						totalBytesRecv += float64(netInterface.BytesRecv)
+						totalBytesSent += float64(netInterface.BytesSent)
+						recentBytesRecv += netInterface.RecvBytesPerSecond
+						recentBytesSent += netInterface.SentBytesPerSecond
 					}
 
-					var recentBytesRecv, recentBytesSent float64
-
-					if totalBytesRecv != 0 {
-						recentBytesRecv = curBytesRecv - totalBytesRecv
-						recentBytesSent = curBytesSent - totalBytesSent
-
-						if int(recentBytesRecv) < 0 {
-							recentBytesRecv = 0
-						}
-						if int(recentBytesSent) < 0 {
-							recentBytesSent = 0
-						}
-						if len(page.NetworkChart.Sparklines[0].Data) > 100 {
-							page.NetworkChart.Sparklines[0].Data = page.NetworkChart.Sparklines[0].Data[1:]
-						}
-						page.NetworkChart.Sparklines[0].Data = append(page.NetworkChart.Sparklines[0].Data, recentBytesRecv)
-						if len(page.NetworkChart.Sparklines[1].Data) > 100 {
-							page.NetworkChart.Sparklines[1].Data = page.NetworkChart.Sparklines[1].Data[1:]
-						}
-						page.NetworkChart.Sparklines[1].Data = append(page.NetworkChart.Sparklines[1].Data, recentBytesSent)
-
+					if len(page.NetworkChart.Sparklines[0].Data) > 100 {
+						page.NetworkChart.Sparklines[0].Data = page.NetworkChart.Sparklines[0].Data[1:]
 					}
-
-					totalBytesRecv = curBytesRecv
-					totalBytesSent = curBytesSent
+					page.NetworkChart.Sparklines[0].Data = append(page.NetworkChart.Sparklines[0].Data, recentBytesRecv)
+					if len(page.NetworkChart.Sparklines[1].Data) > 100 {
+						page.NetworkChart.Sparklines[1].Data = page.NetworkChart.Sparklines[1].Data[1:]
+					}
+					page.NetworkChart.Sparklines[1].Data = append(page.NetworkChart.Sparklines[1].Data, recentBytesSent)
 
 					totalData, units := utils.RoundValues(totalBytesRecv, totalBytesSent, true)
 
@@ -387,28 +377,19 @@ func RenderCPUinfo(ctx context.Context, dataChannel chan *general.CPULoad, refre
 
 		case data := <-dataChannel: // Update chart values
 			if run {
-				page.UsrChart.Percent = data.Usr
-				page.NiceChart.Percent = data.Nice
-				page.SysChart.Percent = data.Sys
-				page.IowaitChart.Percent = data.Iowait
-				page.IrqChart.Percent = data.Irq
-				page.SoftChart.Percent = data.Soft
-				page.StealChart.Percent = data.Steal
-				page.IdleChart.Percent = data.Idle
+				setGaugePercent(page.UsrChart, data.Usr)
+				setGaugePercent(page.NiceChart, data.Nice)
+				setGaugePercent(page.SysChart, data.Sys)
+				setGaugePercent(page.IowaitChart, data.Iowait)
+				setGaugePercent(page.IrqChart, data.Irq)
+				setGaugePercent(page.SoftChart, data.Soft)
+				setGaugePercent(page.StealChart, data.Steal)
+				setGaugePercent(page.IdleChart, data.Idle)
 
 				if numCores > 8 {
-					rows := [][]string{}
-					for j := 0; j < len(data.CPURates[0]); j++ {
-						rows = append(rows, []string{
-							data.CPURates[0][j],
-							data.CPURates[1][j],
-						})
-					}
-
-					page.CPUTable.Rows = rows
-				} else {
-					page.CPUChart.Rows = data.CPURates
+					page.CPUTable.Rows = getCoreLoadRows(data.PerCore)
 				}
+				setCoreChartData(page.CoreChart, data.PerCore)
 
 				on.Do(func() {
 					w, h := ui.TerminalDimensions()
@@ -425,3 +406,96 @@ func RenderCPUinfo(ctx context.Context, dataChannel chan *general.CPULoad, refre
 		}
 	}
 }
+
+// roundOff converts num bytes to gigabytes rounded off to one decimal place.
+func roundOff(num uint64) float64 {
+	x := float64(num) / (1024 * 1024 * 1024)
+	return math.Round(x*10) / 10
+}
+
+// getHostInfoRows formats information about the system for the Info table.
+func getHostInfoRows(info *general.HostInfo) ([]string, [][]string) {
+	header := []string{"Hostname", info.Hostname}
+	rows := [][]string{
+		{"Up Time", utils.SecondsToHuman(int(info.UptimeSeconds))},
+		{"Boot Time", utils.GetDateFromUnix(info.BootTime.UnixNano() / int64(time.Millisecond))},
+		{"Processes", fmt.Sprintf("%d", info.Procs)},
+		{"OS/Platform", fmt.Sprintf("%s/%s %s", info.OS, info.Platform, info.PlatformVersion)},
+		{"Kernel/Arch", fmt.Sprintf("%s/%s", info.KernelVersion, info.KernelArch)},
+	}
+	return header, rows
+}
+
+// getDiskRows formats the usage of filesystems for the Disk table.
+func getDiskRows(diskStats []general.DiskStats) [][]string {
+	rows := [][]string{}
+	for _, d := range diskStats {
+		rows = append(rows, []string{
+			d.Mountpoint,
+			fmt.Sprintf("%.2f G", float64(d.Total)/(1024*1024*1024)),
+			fmt.Sprintf("%.2f %s", d.UsedPercent, "%"),
+			fmt.Sprintf("%.2f G", float64(d.Used)/(1024*1024*1024)),
+			fmt.Sprintf("%.2f G", float64(d.Free)/(1024*1024*1024)),
+			d.Fstype,
+		})
+	}
+	return rows
+}
+
+// getTemperatureRows formats sensor readings for the Temp table.
+func getTemperatureRows(tempStats []general.TemperatureStats) [][]string {
+	rows := [][]string{}
+	for _, t := range tempStats {
+		rows = append(rows, []string{t.Sensor, fmt.Sprintf("%.1f °C", t.Celsius)})
+	}
+	return rows
+}
+
+// getCoreLoadRows formats the load of every core for the CPU table.
+func getCoreLoadRows(perCore []general.CoreLoad) [][]string {
+	rows := [][]string{}
+	for i, cl := range perCore {
+		row := []string{"CPU " + strconv.Itoa(i), fmt.Sprintf("%.1f%%", cl.Busy())}
+		for _, state := range coreStates {
+			row = append(row, fmt.Sprintf("%.1f%%", state.load(cl)))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// maxCoreBarWidth is the widest a bar of the per core chart is drawn.
+const maxCoreBarWidth = 8
+
+// setCoreChartData stacks the time every core spends in each state, sizing
+// the bars so that all cores fit in the chart.
+func setCoreChartData(chart *widgets.StackedBarChart, perCore []general.CoreLoad) {
+	data := make([][]float64, 0, len(perCore))
+	labels := make([]string, 0, len(perCore))
+	for i, cl := range perCore {
+		bar := []float64{}
+		for _, state := range coreStates {
+			bar = append(bar, state.load(cl))
+		}
+		data = append(data, bar)
+		labels = append(labels, strconv.Itoa(i))
+	}
+	chart.Data = data
+	chart.Labels = labels
+
+	if len(perCore) > 0 {
+		chart.BarWidth = chart.Inner.Dx()/len(perCore) - chart.BarGap
+		if chart.BarWidth > maxCoreBarWidth {
+			chart.BarWidth = maxCoreBarWidth
+		} else if chart.BarWidth < 1 {
+			chart.BarWidth = 1
+		}
+	}
+}
+
+// setGaugePercent sets the percentage shown by a gauge, keeping one decimal
+// place in its label.
+func setGaugePercent(gauge *widgets.Gauge, percent float64) {
+	gauge.Percent = int(math.Round(percent))
+	gauge.Label = fmt.Sprintf("%.1f%%", percent)
+}
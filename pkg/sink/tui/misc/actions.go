@@ -42,6 +42,18 @@ var allActions = [][]string{
 	},
 }
 
+var procActions = [][]string{
+	{
+		"RENICE",
+	},
+	{
+		"IONICE",
+	},
+	{
+		"AFFINITY",
+	},
+}
+
 const actionNameIdx = 0
 
 // ActionTable is a wrapper widget around a Table
@@ -68,6 +80,18 @@ func NewActionTable() *ActionTable {
 	return actionTable
 }
 
+// ForCommand sets the actions that can be selected for a specific command
+// and returns the modified ActionTable.
+func (actionTable *ActionTable) ForCommand(command HelpKeybindingType) *ActionTable {
+	switch command {
+	case ProcCommand:
+		actionTable.Table.Rows = procActions
+	default:
+		actionTable.Table.Rows = allActions
+	}
+	return actionTable
+}
+
 // SelectedAction returns an action as string from the selected row of the action table
 func (actionTable *ActionTable) SelectedAction() string {
 	return actionTable.Rows[actionTable.SelectedRow][actionNameIdx]
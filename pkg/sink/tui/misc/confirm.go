@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package misc
+
+import (
+	ui "github.com/gizak/termui/v3"
+	vz "github.com/pesos/grofer/pkg/utils/visualization"
+)
+
+// ConfirmBox is a wrapper widget around a Table meant
+// to ask for confirmation before an action is performed.
+// It implements the ui.Drawable interface.
+type ConfirmBox struct {
+	*vz.Table
+	question    string
+	targets     [][]string
+	keybindings [][]string
+}
+
+// NewConfirmBox is a constructor for the ConfirmBox type.
+func NewConfirmBox() *ConfirmBox {
+	return &ConfirmBox{
+		Table:       vz.NewTable(),
+		keybindings: getConfirmKeybindings(),
+	}
+}
+
+// Resize resizes the widget based on specified width
+// and height.
+func (confirmBox *ConfirmBox) Resize(termWidth, termHeight int) {
+	textWidth := 50
+	if textWidth < len(confirmBox.question) {
+		textWidth = len(confirmBox.question) + 2
+	}
+	for _, line := range confirmBox.targets {
+		if textWidth < len(line[0]) {
+			textWidth = len(line[0]) + 2
+		}
+	}
+	textHeight := len(confirmBox.targets) + len(confirmBox.keybindings) + 4
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	confirmBox.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget.
+func (confirmBox *ConfirmBox) Draw(buf *ui.Buffer) {
+	confirmBox.Table.Title = " Confirm "
+	confirmBox.Table.Header = []string{confirmBox.question}
+	confirmBox.Table.Rows = append([][]string{}, confirmBox.targets...)
+	confirmBox.Table.Rows = append(confirmBox.Table.Rows, confirmBox.keybindings...)
+	confirmBox.Table.BorderStyle.Fg = ui.ColorMagenta
+	confirmBox.Table.BorderStyle.Bg = ui.ColorClear
+	confirmBox.Table.ColResizer = func() {
+		x := confirmBox.Table.Inner.Dx()
+		confirmBox.Table.ColWidths = []int{x}
+	}
+	confirmBox.Table.Draw(buf)
+}
+
+// SetQuestion sets the action to be confirmed and the
+// targets it will be performed on.
+func (confirmBox *ConfirmBox) SetQuestion(question string, targets []string) {
+	confirmBox.question = question
+	confirmBox.targets = make([][]string, 0, len(targets))
+	for _, target := range targets {
+		confirmBox.targets = append(confirmBox.targets, []string{"  - " + target})
+	}
+}
+
+// ensure interface compliance.
+var _ ui.Drawable = (*ConfirmBox)(nil)
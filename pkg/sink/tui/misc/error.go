@@ -17,6 +17,8 @@ limitations under the License.
 package misc
 
 import (
+	"fmt"
+
 	ui "github.com/gizak/termui/v3"
 	vz "github.com/pesos/grofer/pkg/utils/visualization"
 )
@@ -26,11 +28,19 @@ import (
 // implements the ui.Drawable interface.
 type ErrorBox struct {
 	*vz.Table
+	title        string
 	errorMessage string
-	err          error
+	lines        [][]string
 	keybindings  [][]string
 }
 
+// ActionResult is the outcome of an action performed on one of
+// several targets, ex - a signal sent to one of the selected processes.
+type ActionResult struct {
+	Target string
+	Err    error
+}
+
 // NewErrorBox is a constructor for the ErrorBox type.
 func NewErrorBox() *ErrorBox {
 	return &ErrorBox{
@@ -43,12 +53,15 @@ func NewErrorBox() *ErrorBox {
 // and height.
 func (errBox *ErrorBox) Resize(termWidth, termHeight int) {
 	textWidth := 50
-	for _, line := range errBox.Rows {
+	if textWidth < len(errBox.errorMessage) {
+		textWidth = len(errBox.errorMessage) + 2
+	}
+	for _, line := range errBox.lines {
 		if textWidth < len(line[0]) {
 			textWidth = len(line[0]) + 2
 		}
 	}
-	textHeight := len(errBox.keybindings) + 5
+	textHeight := len(errBox.lines) + len(errBox.keybindings) + 4
 	x := (termWidth - textWidth) / 2
 	y := (termHeight - textHeight) / 2
 	if x < 0 {
@@ -65,9 +78,9 @@ func (errBox *ErrorBox) Resize(termWidth, termHeight int) {
 
 // Draw puts the required text into the widget.
 func (errBox *ErrorBox) Draw(buf *ui.Buffer) {
-	errBox.Table.Title = " Error "
+	errBox.Table.Title = errBox.title
 	errBox.Table.Header = []string{errBox.errorMessage}
-	errBox.Table.Rows = [][]string{{errBox.err.Error()}}
+	errBox.Table.Rows = append([][]string{}, errBox.lines...)
 	errBox.Table.Rows = append(errBox.Table.Rows, errBox.keybindings...)
 	errBox.Table.BorderStyle.Fg = ui.ColorCyan
 	errBox.Table.BorderStyle.Bg = ui.ColorClear
@@ -80,8 +93,26 @@ func (errBox *ErrorBox) Draw(buf *ui.Buffer) {
 
 // SetErrorString sets the error string to be displayed.
 func (errBox *ErrorBox) SetErrorString(errStr string, err error) {
+	errBox.title = " Error "
 	errBox.errorMessage = errStr
-	errBox.err = err
+	errBox.lines = [][]string{{err.Error()}}
+}
+
+// SetResults sets the outcome of an action performed on several
+// targets to be displayed, listing the successes and the failures.
+func (errBox *ErrorBox) SetResults(action string, results []ActionResult) {
+	failed := 0
+	errBox.lines = make([][]string, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			errBox.lines = append(errBox.lines, []string{"FAILED  " + result.Target + ": " + result.Err.Error()})
+		} else {
+			errBox.lines = append(errBox.lines, []string{"OK      " + result.Target})
+		}
+	}
+	errBox.title = " Results "
+	errBox.errorMessage = fmt.Sprintf("%s: %d succeeded, %d failed", action, len(results)-failed, failed)
 }
 
 // ensure interface compliance.
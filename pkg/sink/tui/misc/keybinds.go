@@ -30,12 +30,21 @@ const (
 	// PerProcCommand is the keybinding identifier
 	// for the `grofer proc -p <pid>` command.
 	PerProcCommand
+	// CompareProcCommand is the keybinding identifier
+	// for the `grofer proc -p <pid>,<pid>` command.
+	CompareProcCommand
 	// ContainerCommand is the keybinding identifier
 	// for the `grofer container` command.
 	ContainerCommand
 	// PerContainerCommand is the keybinding identifier
 	// for the `grofer container -c <CID>` command.
 	PerContainerCommand
+	// ReadOnlyProcCommand is the keybinding identifier
+	// for the `grofer proc --read-only` command.
+	ReadOnlyProcCommand
+	// ReadOnlyContainerCommand is the keybinding identifier
+	// for the `grofer container --read-only` command.
+	ReadOnlyContainerCommand
 )
 
 // getHelpKeybindingsForCommand returns the help keybinding for a specific command.
@@ -47,15 +56,55 @@ func getHelpKeybindingsForCommand(forCommand HelpKeybindingType) [][]string {
 		return getProcCommandKeybindings()
 	case PerProcCommand:
 		return getPerProcCommandKeybindings()
+	case CompareProcCommand:
+		return getCompareProcCommandKeybindings()
 	case ContainerCommand:
 		return getContainerCommandKeybindings()
 	case PerContainerCommand:
 		return getPerContainerCommandKeybindings()
+	case ReadOnlyProcCommand:
+		return withoutSections(getProcCommandKeybindings(),
+			"Marking processes", "Process actions", "Action selection", "Signal selection")
+	case ReadOnlyContainerCommand:
+		return withoutSections(getContainerCommandKeybindings(),
+			"Marking containers", "Container actions", "Action selection")
 	default:
 		return getDefaultHelpKeybinding()
 	}
 }
 
+// withoutSections returns the keybindings without the sections that start
+// with the given headings, ex - the actions hidden in read-only mode.
+func withoutSections(keybindings [][]string, headings ...string) [][]string {
+	hidden := make(map[string]bool, len(headings))
+	for _, heading := range headings {
+		hidden[heading] = true
+	}
+
+	result := [][]string{}
+	skipping := false
+	for _, line := range keybindings {
+		if hidden[line[0]] {
+			skipping = true
+		}
+		if !skipping {
+			result = append(result, line)
+		}
+		if line[0] == "" {
+			skipping = false
+		}
+	}
+	return result
+}
+
+func getConfirmKeybindings() [][]string {
+	return [][]string{
+		{""},
+		{"To confirm: y"},
+		{"To cancel: n or <Esc>"},
+	}
+}
+
 func getErrorKeybindings() [][]string {
 	return getDefaultHelpKeybinding()
 }
@@ -105,17 +154,54 @@ func getProcCommandKeybindings() [][]string {
 		{"  - Use column number to sort ascending."},
 		{"  - Use <F-column number> to sort descending."},
 		{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
+		{"  - s: Sort on the next column, reaching every column"},
+		{"  - r: Reverse the direction of the sort"},
 		{"  - 0: Disable Sort"},
 		{""},
+		{"Filtering"},
+		{"  - /: Filter by a regex over PID, command, command line or user"},
+		{"  - <Enter>: Keep the filter and close the prompt"},
+		{"  - <Esc>: Clear the filter and close the prompt"},
+		{""},
+		{"Process tree"},
+		{"  - t: Toggle tree view"},
+		{"  - - and +: Collapse or expand the selected subtree"},
+		{""},
+		{"Process events"},
+		{"  - e: Show or hide the log of started and exited processes"},
+		{"  - <Tab>: Move between the process table and the log"},
+		{""},
+		{"Grouping"},
+		{"  - m: Group by command, then user, then cgroup, then ungroup"},
+		{"  - <Enter>: Show the processes of the selected group"},
+		{"  - <Esc>: Go back to the groups"},
+		{""},
+		{"Marking processes"},
+		{"  - <Space>: Mark or unmark the selected process"},
+		{"  - A: Mark every process matching the filter"},
+		{"  - U: Unmark every process"},
+		{""},
+		{"Comparing processes"},
+		{"  - c: Compare the marked processes, or the selected one"},
+		{"  - <Esc>: Go back to the process table"},
+		{""},
 		{"Process actions"},
-		{"  - K and <F9>: Open signal selector menu"},
+		{"  - K and <F9>: Open signal selector menu, for the marked processes if any"},
+		{"  - a: Open action selector menu (renice, ionice, CPU affinity)"},
+		{""},
+		{"Action selection"},
+		{"  - k and <Up>: up"},
+		{"  - j and <Down>: down"},
+		{"  - <Enter>: prompt for the new value of the highlighted action"},
+		{"  - <Enter> in the prompt: apply the value to every thread of the process"},
+		{"  - <Esc>: close action selector or prompt"},
 		{""},
 		{"Signal selection"},
-		{"  - K and <F9>: Send SIGTERM to selected process. Kills the process"},
+		{"  - K and <F9>: Send SIGTERM to selected process, once confirmed"},
 		{"  - k and <Up>: up"},
 		{"  - j and <Down>: down"},
 		{"  - 0-9: navigate by numeric index"},
-		{"  - <Enter>: send highlighted signal to process"},
+		{"  - <Enter>: send highlighted signal to process, once confirmed"},
 		{"  - <Esc>: close signal selector"},
 		{""},
 		{"To close this prompt: <Esc>"},
@@ -137,6 +223,27 @@ func getPerProcCommandKeybindings() [][]string {
 		{"  - <C-f>: full page down"},
 		{"  - gg and <Home>: jump to top"},
 		{"  - G and <End>: jump to bottom"},
+		{"  - <Tab>: switch between child processes, threads and open files"},
+		{""},
+		{"Sorting threads"},
+		{"  - Use column number to sort ascending."},
+		{"  - Use <F-column number> to sort descending."},
+		{"  - 0: Disable Sort"},
+		{""},
+		{"Details"},
+		{"  - d: Show arguments, environment, limits, cgroups, namespaces and scheduling"},
+		{"  - h and <Left>, l and <Right>: previous and next tab"},
+		{"  - 1-6: jump to a tab"},
+		{"  - <Esc>: close the details"},
+		{""},
+		{"To close this prompt: <Esc>"},
+	}
+}
+
+func getCompareProcCommandKeybindings() [][]string {
+	return [][]string{
+		{"Quit: q or <C-c>"},
+		{"Pause Rendering: p"},
 		{""},
 		{"To close this prompt: <Esc>"},
 	}
@@ -163,13 +270,18 @@ func getContainerCommandKeybindings() [][]string {
 		{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
 		{"  - 0: Disable Sort"},
 		{""},
+		{"Marking containers"},
+		{"  - <Space>: Mark or unmark the selected container"},
+		{"  - A: Mark every container"},
+		{"  - U: Unmark every container"},
+		{""},
 		{"Container actions"},
-		{"  - <Enter>: Open action selector menu"},
+		{"  - <Enter>: Open action selector menu, for the marked containers if any"},
 		{""},
 		{"Action selection"},
 		{"  - k and <Up>: up"},
 		{"  - j and <Down>: down"},
-		{"  - <Enter>: perform highlighted action"},
+		{"  - <Enter>: perform highlighted action, once confirmed for restart, stop, kill and remove"},
 		{"  - <Esc>: close action selector"},
 		{""},
 		{"To close this prompt: <Esc>"},
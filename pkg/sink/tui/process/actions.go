@@ -0,0 +1,111 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"runtime"
+	"strconv"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/shirou/gopsutil/cpu"
+)
+
+// procAction is an action of the action menu that changes a setting of a
+// process to the value typed in a prompt.
+type procAction struct {
+	prompt   string                          // describes the value to type.
+	failure  string                          // describes a failed action in the error box.
+	current  func(p process.Snapshot) string // the current setting, to start the prompt with.
+	validate func(input string) error
+	apply    func(pid int32, input string) error
+}
+
+// procActions are the actions of the action menu, by name.
+var procActions = map[string]procAction{
+	"RENICE": {
+		prompt:  "Nice value (-20 to 19)",
+		failure: "Error changing nice value of process",
+		current: func(p process.Snapshot) string {
+			return strconv.Itoa(int(p.Nice))
+		},
+		validate: func(input string) error {
+			_, err := process.ParseNice(input)
+			return err
+		},
+		apply: func(pid int32, input string) error {
+			nice, err := process.ParseNice(input)
+			if err != nil {
+				return err
+			}
+			return process.SetNice(pid, nice)
+		},
+	},
+	"IONICE": {
+		prompt:  "I/O class and level (realtime:0-7, best-effort:0-7, idle or none)",
+		failure: "Error changing I/O priority of process",
+		current: func(p process.Snapshot) string {
+			prio, err := process.GetIOPriority(p.PID)
+			if err != nil {
+				return ""
+			}
+			return prio.String()
+		},
+		validate: func(input string) error {
+			_, err := process.ParseIOPriority(input)
+			return err
+		},
+		apply: func(pid int32, input string) error {
+			prio, err := process.ParseIOPriority(input)
+			if err != nil {
+				return err
+			}
+			return process.SetIOPriority(pid, prio)
+		},
+	},
+	"AFFINITY": {
+		prompt:  "CPUs to run on (e.g. 0-3,6)",
+		failure: "Error changing CPU affinity of process",
+		current: func(p process.Snapshot) string {
+			cpus, err := process.GetAffinity(p.PID)
+			if err != nil {
+				return ""
+			}
+			return process.FormatCPUList(cpus)
+		},
+		validate: func(input string) error {
+			_, err := process.ParseCPUList(input, numCPU())
+			return err
+		},
+		apply: func(pid int32, input string) error {
+			cpus, err := process.ParseCPUList(input, numCPU())
+			if err != nil {
+				return err
+			}
+			return process.SetAffinity(pid, cpus)
+		},
+	},
+}
+
+// numCPU returns the number of logical CPUs of the host, which may be
+// more than the CPUs grofer itself is allowed to run on.
+func numCPU() int {
+	count, err := cpu.Counts(true)
+	if err != nil || count == 0 {
+		return runtime.NumCPU()
+	}
+	return count
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/sink/tui/misc"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestProcActionsMatchMenu(t *testing.T) {
+	actions := misc.NewActionTable().ForCommand(misc.ProcCommand)
+	for _, row := range actions.Rows {
+		_, ok := procActions[row[0]]
+		utils.Assert(t, ok, "no action named %s", row[0])
+	}
+	utils.Equals(t, len(procActions), len(actions.Rows))
+}
+
+func TestRenicePrompt(t *testing.T) {
+	renice := procActions["RENICE"]
+	utils.Equals(t, "-5", renice.current(process.Snapshot{Nice: -5}))
+	utils.Raises(t, renice.validate("10"))
+	utils.Assert(t, renice.validate("42") != nil, "expected an error for an out of range nice value")
+}
@@ -20,80 +20,71 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/process"
 	"github.com/pesos/grofer/pkg/sink/tui/misc"
-	"github.com/pesos/grofer/pkg/utils"
 	viz "github.com/pesos/grofer/pkg/utils/visualization"
-	proc "github.com/shirou/gopsutil/process"
 )
 
-func getData(procs []*proc.Process) [][]string {
-	procData := [][]string{}
+// getData returns the rows of the table along with the PID of every row.
+func getData(procs []process.Snapshot, cols []column) ([][]string, []int32) {
+	procData := make([][]string, 0, len(procs))
+	pids := make([]int32, 0, len(procs))
 	for _, p := range procs {
-		// Get command
-		cmd := ""
-		exe, err := p.Exe()
-		if err == nil {
-			cmds := strings.Split(exe, "/")
-			cmd = cmds[len(cmds)-1]
-
-			// Get CPU
-			cpu := ""
-			cpuPercent, err := p.CPUPercent()
-			if err == nil {
-				cpu = fmt.Sprintf("%.2f%%", cpuPercent)
-			}
-
-			// Get Mem
-			mem := ""
-			memPercent, err := p.MemoryPercent()
-			if err == nil {
-				mem = fmt.Sprintf("%.2f%%", memPercent)
-			}
-
-			// Get Status
-			status, _ := p.Status()
-
-			// Get Foreground
-			fg, _ := p.Foreground()
+		procData = append(procData, getRow(p, cols))
+		pids = append(pids, p.PID)
+	}
 
-			// Get Creation time
-			t, err := p.CreateTime()
-			ctime := ""
-			if err == nil {
-				ctime = utils.GetDateFromUnix(t)
-			}
+	return procData, pids
+}
 
-			// Get Thread Count
-			tc, _ := p.NumThreads()
-
-			// Aggregate row
-			r := []string{
-				fmt.Sprintf("%d", p.Pid),
-				cmd,
-				cpu,
-				mem,
-				status,
-				fmt.Sprintf("%t", fg),
-				ctime,
-				fmt.Sprintf("%d", tc),
-			}
-			procData = append(procData, r)
+// editInput returns the text of a prompt after a key is pressed in it.
+func editInput(text, key string) string {
+	switch key {
+	case "<Backspace>", "<C-<Backspace>>":
+		if runes := []rune(text); len(runes) > 0 {
+			return string(runes[:len(runes)-1])
+		}
+	case "<Space>":
+		return text + " "
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			return text + key
 		}
 	}
+	return text
+}
 
-	return procData
+// procKeys returns the key identifying every process by PID.
+func procKeys(procs []process.Snapshot) map[int32]process.Key {
+	keys := make(map[int32]process.Key, len(procs))
+	for _, p := range procs {
+		keys[p.PID] = p.Key()
+	}
+	return keys
 }
 
-// AllProcVisuals renders the all process page
-func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refreshRate uint64) error {
+// AllProcVisuals renders the all process page with the given columns, or
+// the default columns if none are given, along with the processes started
+// and exited recorded by the event log. The processes received are those
+// selected by cmdFilter, which is shown in the title. Actions on processes
+// are refused by the guard for protected processes, and hidden if it is
+// read-only.
+func AllProcVisuals(ctx context.Context, dataChannel chan []process.Snapshot, events *process.EventLog, refreshRate uint64, columnNames []string, cmdFilter *process.Filter, guard *process.Guard) error {
+	cols, err := getColumns(columnNames)
+	if err != nil {
+		return err
+	}
+
 	if err := ui.Init(); err != nil {
 		log.Fatalf("failed to initialize termui: %v", err)
 	}
@@ -101,27 +92,30 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 	defer ui.Close()
 
 	var on sync.Once
-	var signals *misc.SignalTable = misc.NewSignalTable()
 	var help *misc.HelpMenu = misc.NewHelpMenu().ForCommand(misc.ProcCommand)
 	var errorBox *misc.ErrorBox = misc.NewErrorBox()
+	if guard.ReadOnly() {
+		help = help.ForCommand(misc.ReadOnlyProcCommand)
+	}
 
-	page := newAllProcPage()
+	page := newAllProcPage(cols)
 	utilitySelected := core.None
+
+	// marked processes and the signal or action being performed
+	bulk := newBulkActions(guard, page.PromptBox, errorBox)
+
+	// comparison of the marked processes, fed by its own watcher while
+	// it is shown
+	var cmp *comparison
+	var cmpChannel chan []*process.Process
+	stopCompare := func() {}
+	defer func() { stopCompare() }()
 	var scrollableWidget viz.ScrollableWidget = page.ProcTable
 	scrollableWidget.EnableCursor()
 
 	sortIdx := -1
 	sortAsc := false
-	header := []string{
-		"PID",
-		"Command",
-		"CPU",
-		"Memory",
-		"Status",
-		"Foreground",
-		"Creation Time",
-		"Thread Count",
-	}
+	header := append([]string{}, page.ProcTable.Header...)
 
 	previousKey := ""
 	selectedStyle := page.ProcTable.CursorColor
@@ -144,13 +138,40 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 			errorBox.Resize(w, h)
 			ui.Render(errorBox)
 
+		case core.Confirm:
+			ui.Render(page.Grid)
+			bulk.confirmBox.Resize(w, h)
+			ui.Render(bulk.confirmBox)
+
+		case core.Filter:
+			ui.Render(page.Grid)
+			page.FilterBox.SetRect(0, h-3, w, h)
+			ui.Render(page.FilterBox)
+
 		case core.Kill:
 			page.ProcTable.CursorColor = killingStyle
-			signals.SetRect(0, 0, w/6, h)
+			bulk.signals.SetRect(0, 0, w/6, h)
+			page.Grid.SetRect(w/6, 0, w, h)
+			ui.Render(bulk.signals)
+			ui.Render(page.Grid)
+
+		case core.Action:
+			page.ProcTable.CursorColor = killingStyle
+			bulk.actions.SetRect(0, 0, w/6, h)
 			page.Grid.SetRect(w/6, 0, w, h)
-			ui.Render(signals)
+			ui.Render(bulk.actions)
 			ui.Render(page.Grid)
 
+		case core.Prompt:
+			page.ProcTable.CursorColor = killingStyle
+			ui.Render(page.Grid)
+			page.PromptBox.SetRect(0, h-3, w, h)
+			ui.Render(page.PromptBox)
+
+		case core.Compare:
+			cmp.page.Grid.SetRect(0, 0, w, h)
+			ui.Render(cmp.page.Grid)
+
 		default:
 			page.ProcTable.CursorColor = selectedStyle
 			ui.Render(page.Grid)
@@ -169,18 +190,192 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 	t := time.NewTicker(time.Duration(refreshRate) * time.Millisecond)
 	tick := t.C
 
-	// updates process list immediately
+	// latest snapshot of processes received and the one being shown.
+	var procs, shown []process.Snapshot
+
+	// whether processes are shown as a tree, and the PIDs of
+	// the processes whose children are hidden in the tree.
+	treeMode := false
+	collapsed := make(map[int32]bool)
+
+	// the filter being typed and the last valid filter applied to the rows.
+	filterText := ""
+	var filter *process.Filter
+
+	// PID of the process shown in every row of the table
+	var rowPIDs []int32
+
+	// index of the grouping applied to the rows, -1 if processes are not
+	// grouped, and the key of the group shown in every row of the table.
+	// Once a group is opened its members are shown instead.
+	groupIdx := -1
+	var rowKeys []string
+	inGroup := false
+	groupKey := ""
+
+	// returns the processes matching the filter, only the members of the
+	// group if one is opened
+	visibleProcs := func() []process.Snapshot {
+		visible := filter.Apply(shown)
+		if inGroup {
+			visible = groupMembers(visible, groupings[groupIdx], groupKey)
+		}
+		return visible
+	}
+
+	// rebuilds the table rows from the snapshot being shown
+	setRows := func() {
+		visible := visibleProcs()
+		if groupIdx != -1 && !inGroup {
+			page.ProcTable.Rows, rowKeys = getGroupRows(groupProcs(visible, groupings[groupIdx]), sortIdx, sortAsc)
+			page.ProcTable.MarkedRows = nil
+			rowPIDs = nil
+			return
+		}
+		if treeMode {
+			page.ProcTable.Rows, rowPIDs = newProcTree(visible).getRows(cols, collapsed, sortIdx, sortAsc)
+		} else {
+			if sortIdx != -1 {
+				visible = append([]process.Snapshot{}, visible...)
+				sortProcs(visible, cols[sortIdx], sortAsc)
+			}
+			page.ProcTable.Rows, rowPIDs = getData(visible, cols)
+		}
+
+		page.ProcTable.MarkedRows = bulk.markedRows(rowPIDs, procKeys(shown))
+	}
+
+	// describes the view and filters in the table title
+	setTitle := func() {
+		parts := []string{}
+		if guard.ReadOnly() {
+			parts = append(parts, "Read-only")
+		}
+		if groupIdx != -1 {
+			if inGroup {
+				parts = append(parts, fmt.Sprintf("Group: %s (<Esc> to go back)", groupKey))
+			} else {
+				parts = append(parts, "Grouped by "+groupings[groupIdx].name)
+			}
+		}
+		if treeMode && (groupIdx == -1 || inGroup) {
+			parts = append(parts, "Process Tree: CPU and Memory include children")
+		}
+		if pattern := cmdFilter.String(); pattern != "" {
+			parts = append(parts, "--filter: "+pattern)
+		}
+		if user := cmdFilter.User(); user != "" {
+			parts = append(parts, "--user: "+user)
+		}
+		if pattern := filter.String(); pattern != "" {
+			parts = append(parts, "Filter: "+pattern)
+		}
+		if len(bulk.marked) > 0 {
+			parts = append(parts, fmt.Sprintf("Marked: %d", len(bulk.marked)))
+		}
+		page.ProcTable.Title = ""
+		if len(parts) > 0 {
+			page.ProcTable.Title = " " + strings.Join(parts, " | ") + " "
+		}
+	}
+	setTitle()
+
+	// sorts the rows on the column at idx, marking it in the header with
+	// the direction of the sort, or restores their order if idx is -1
+	setSort := func(idx int, asc bool) {
+		page.ProcTable.Header = append([]string{}, header...)
+		sortIdx, sortAsc = idx, asc
+		if idx != -1 {
+			arrow := viz.DownArrow
+			if asc {
+				arrow = viz.UpArrow
+			}
+			page.ProcTable.Header[idx] = header[idx] + " " + arrow
+		}
+		setRows()
+	}
+
+	// switches the table between groups and processes, resetting the sort
+	setView := func() {
+		if groupIdx != -1 && !inGroup {
+			page.setGroupColumns()
+		} else {
+			page.setColumns(cols)
+		}
+		header = append([]string{}, page.ProcTable.Header...)
+		sortIdx = -1
+		setTitle()
+		setRows()
+		page.ProcTable.ScrollTop()
+	}
+
+	// whether the log of started and exited processes is shown
+	eventsShown := false
+	setEvents := func() {
+		logged := events.Events()
+		page.EventsTable.Title = getEventsTitle(logged)
+		page.EventsTable.Rows = getEventRows(logged)
+	}
+
+	// updates process list immediately, forgetting the marks of
+	// processes that have exited
 	updateProcs := func() {
 		if runAllProc {
-			procs, err := proc.Processes()
-			if err == nil {
-				page.ProcTable.Rows = getData(procs)
+			if eventsShown {
+				setEvents()
 			}
+			shown = procs
+			bulk.forgetExited(shown)
+			setTitle()
+			setRows()
 		}
 	}
 
-	// whether a process is selected for killing (UI controls are paused)
-	var pidToKill int32
+	// opens the comparison of the marked processes, or of the selected
+	// process if none are marked
+	openCompare := func() {
+		pids := bulk.markedPIDs()
+		if len(pids) == 0 {
+			if page.ProcTable.SelectedRow >= len(rowPIDs) {
+				return
+			}
+			pids = append(pids, rowPIDs[page.ProcTable.SelectedRow])
+		}
+		if len(pids) > MaxCompared {
+			errorBox.SetErrorString("Cannot compare processes",
+				fmt.Errorf("%d processes are marked, at most %d can be compared", len(pids), MaxCompared))
+			utilitySelected = core.Error
+			return
+		}
+		sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+		var cmpCtx context.Context
+		cmpCtx, stopCompare = context.WithCancel(ctx)
+		cmpChannel = make(chan []*process.Process, 1)
+		go watchProcs(cmpCtx, pids, refreshRate, cmpChannel)
+		cmp = newComparison()
+		utilitySelected = core.Compare
+	}
+
+	// closes the comparison and stops its watcher
+	closeCompare := func() {
+		stopCompare()
+		stopCompare = func() {}
+		cmpChannel = nil
+		cmp = nil
+		utilitySelected = core.None
+	}
+
+	// closes the signal selector, the action selector or prompt, or the
+	// confirmation and resumes the updates of the table
+	closeAction := func() {
+		page.ProcTable.CursorColor = selectedStyle
+		scrollableWidget.DisableCursor()
+		scrollableWidget = page.ProcTable
+		scrollableWidget.EnableCursor()
+		runAllProc = true
+		updateProcs()
+	}
 	var handledPreviousKey bool
 
 	for {
@@ -190,6 +385,83 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 			return ctx.Err()
 
 		case e := <-uiEvents:
+			// while the filter prompt is open keys edit the filter, which
+			// is applied as it is typed.
+			if utilitySelected == core.Filter {
+				switch e.ID {
+				case "<C-c>":
+					return core.ErrCanceledByUser
+				case "<Enter>":
+					utilitySelected = core.None
+				case "<Escape>":
+					filterText = ""
+					utilitySelected = core.None
+				default:
+					filterText = editInput(filterText, e.ID)
+				}
+
+				page.FilterBox.Text = "/" + filterText
+				if f, err := process.NewFilter(filterText, ""); err == nil {
+					filter = f
+					page.FilterBox.Title = " Filter "
+				} else {
+					page.FilterBox.Title = " Filter: invalid regular expression "
+				}
+				setRows()
+				setTitle()
+				page.ProcTable.ScrollTop()
+				updateUI()
+				continue
+			}
+
+			// while an action prompt is open keys edit its value, which is
+			// validated as it is typed and applied on <Enter>.
+			if utilitySelected == core.Prompt {
+				if e.ID == "<C-c>" {
+					return core.ErrCanceledByUser
+				}
+				if utilitySelected = bulk.editPrompt(e.ID); utilitySelected != core.Prompt {
+					closeAction()
+				}
+				updateUI()
+				continue
+			}
+
+			// while processes are compared the table is not shown
+			if utilitySelected == core.Compare {
+				switch e.ID {
+				case "q", "<C-c>":
+					return core.ErrCanceledByUser
+				case "<Escape>":
+					closeCompare()
+				case "p":
+					pause()
+					if runAllProc {
+						cmp.draw()
+					}
+				}
+				updateUI()
+				continue
+			}
+
+			// while a confirmation is asked for only its answer is handled
+			if utilitySelected == core.Confirm {
+				if e.ID == "<C-c>" {
+					return core.ErrCanceledByUser
+				}
+				if utilitySelected = bulk.answer(e.ID); utilitySelected != core.Confirm {
+					closeAction()
+				}
+				if utilitySelected == core.Error {
+					// the outcome of the signal may list several processes
+					scrollableWidget.DisableCursor()
+					scrollableWidget = errorBox.Table
+					scrollableWidget.EnableCursor()
+				}
+				updateUI()
+				continue
+			}
+
 			switch e.ID {
 			case "q", "<C-c>": //q or Ctrl-C to quit
 				return core.ErrCanceledByUser
@@ -208,6 +480,20 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 				pause()
 
 			case "<Escape>":
+				if utilitySelected == core.None && inGroup {
+					// go back to the groups, selecting the one left
+					inGroup = false
+					setView()
+					for i, key := range rowKeys {
+						if key == groupKey {
+							page.ProcTable.ScrollToIndex(i)
+							break
+						}
+					}
+				}
+				if utilitySelected == core.Action || utilitySelected == core.Kill {
+					runAllProc = true
+				}
 				utilitySelected = core.None
 				scrollableWidget.DisableCursor()
 				scrollableWidget = page.ProcTable
@@ -246,114 +532,171 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 
 			// handle actions
 			case "K", "<F9>":
-				if utilitySelected == core.None {
-					if page.ProcTable.SelectedRow < len(page.ProcTable.Rows) {
-						// get PID from the data
-						row := page.ProcTable.Rows[page.ProcTable.SelectedRow]
-						pid, err := strconv.Atoi(row[0])
-						if err != nil {
-							return fmt.Errorf("failed to get PID of process: %v", err)
-						}
-
-						// Set pid to kill
-						pidToKill = int32(pid)
+				if utilitySelected == core.None && !guard.ReadOnly() {
+					// select the marked processes if any
+					if utilitySelected = bulk.openKill(shown, rowPIDs, page.ProcTable.SelectedRow); utilitySelected == core.Kill {
 						runAllProc = false
 
 						// open the signal selector
-						utilitySelected = core.Kill
-						scrollableWidget = signals.Table
+						scrollableWidget = bulk.signals.Table
 						scrollableWidget.EnableCursor()
 					}
 				} else if utilitySelected == core.Kill {
-					// get process and kill it
-					procToKill, err := proc.NewProcess(pidToKill)
-					page.ProcTable.CursorColor = selectedStyle
-					if err == nil {
-						err = procToKill.SendSignal(syscall.SIGTERM)
-						if err != nil {
-							errorBox.SetErrorString(fmt.Sprintf("Error killing process: %d", pidToKill), err)
-							utilitySelected = core.Error
-						}
-					} else {
-						errorBox.SetErrorString(fmt.Sprintf("Process not found: %d", pidToKill), err)
-						utilitySelected = core.Error
-					}
+					utilitySelected = bulk.confirmKill(syscall.SIGTERM, "SIGTERM")
+				}
 
-					scrollableWidget = page.ProcTable
-					scrollableWidget.EnableCursor()
-					runAllProc = true
-					updateProcs()
+			case "a":
+				if utilitySelected == core.None && !guard.ReadOnly() && page.ProcTable.SelectedRow < len(rowPIDs) {
+					key := procKeys(shown)[rowPIDs[page.ProcTable.SelectedRow]]
+					if utilitySelected = bulk.openAction(key); utilitySelected == core.Action {
+						runAllProc = false
+
+						// open the action selector
+						scrollableWidget = bulk.actions.Table
+						scrollableWidget.EnableCursor()
+					}
 				}
 
 			case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
-				/*
-				* The signal selector can be navigated by entering the number beside the
-				* desired signal. Double digit numbers are handled by checking the previous
-				* key and, if it is among 1,2 and 3, navigate to the corresponding double
-				* digit number (as there are currently 31 supported signals).
-				* For example, pressing 25 would first navigate to signal 2, then to signal 25
-				 */
+				// the signal selector is navigated by the number of a signal
 				if utilitySelected == core.Kill {
-					scrollIdx, _ := strconv.Atoi(e.ID)
-					if _, checkPrev := map[string]bool{"1": true, "2": true, "3": true}[previousKey]; checkPrev {
-						prevIdx, _ := strconv.Atoi(previousKey)
-						scrollIdx = 10*prevIdx + scrollIdx
-						handledPreviousKey = true
-					}
-					signals.Table.ScrollToIndex(scrollIdx - 1) // account for 0-indexing
-					ui.Render(signals)
+					handledPreviousKey = bulk.scrollToSignal(e.ID, previousKey)
+					ui.Render(bulk.signals)
 				} else if utilitySelected == core.None {
 					switch e.ID {
 					// Sort Ascending
-					case "1", "2", "3", "4", "5", "6", "7", "8":
-						page.ProcTable.Header = append([]string{}, header...)
+					case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 						idx, _ := strconv.Atoi(e.ID)
-						sortIdx = idx - 1
-						page.ProcTable.Header[sortIdx] = header[sortIdx] + " " + viz.UpArrow
-						sortAsc = true
-						utils.SortData(page.ProcTable.Rows, sortIdx, sortAsc, "PROCS")
+						if idx <= len(header) {
+							setSort(idx-1, true)
+						}
 
 					// Disable Sort
 					case "0":
-						page.ProcTable.Header = append([]string{}, header...)
-						sortIdx = -1
+						setSort(-1, sortAsc)
 					}
 				}
 
 			// Sort Descending
 			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>", "<F8>":
+				idx, _ := strconv.Atoi(e.ID[2:3])
+				if utilitySelected == core.None && idx <= len(header) {
+					setSort(idx-1, false)
+				}
+
+			// Sort on the next column, reaching the columns past those
+			// bound to the number and function keys
+			case "s":
+				if utilitySelected == core.None && len(header) > 0 {
+					setSort((sortIdx+1)%len(header), sortAsc)
+				}
+
+			// Reverse the direction of the sort
+			case "r":
+				if utilitySelected == core.None && sortIdx != -1 {
+					setSort(sortIdx, !sortAsc)
+				}
+
+			case "/":
 				if utilitySelected == core.None {
-					page.ProcTable.Header = append([]string{}, header...)
-					idx, _ := strconv.Atoi(e.ID[2:3])
-					sortIdx = idx - 1
-					page.ProcTable.Header[sortIdx] = header[sortIdx] + " " + viz.DownArrow
-					sortAsc = false
-					utils.SortData(page.ProcTable.Rows, sortIdx, sortAsc, "PROCS")
+					page.FilterBox.Text = "/" + filterText
+					utilitySelected = core.Filter
 				}
 
-			case "<Enter>":
-				if utilitySelected == core.Kill {
-					signalToSend := signals.SelectedSignal()
-					procToKill, err := proc.NewProcess(pidToKill)
-					page.ProcTable.CursorColor = selectedStyle
-					if err == nil {
-						err = procToKill.SendSignal(signalToSend)
-						if err != nil {
-							errorBox.SetErrorString(fmt.Sprintf("Error killing process: %d", pidToKill), err)
-							utilitySelected = core.Error
-						}
+			// handle grouping, cycling through the groupings and back
+			case "m":
+				if utilitySelected == core.None {
+					groupIdx++
+					if groupIdx == len(groupings) {
+						groupIdx = -1
+					}
+					inGroup = false
+					setView()
+				}
+
+			// handle the process events log
+			case "e":
+				if utilitySelected == core.None {
+					eventsShown = !eventsShown
+					page.showEvents(eventsShown)
+					if eventsShown {
+						setEvents()
+					} else if scrollableWidget == page.EventsTable {
+						scrollableWidget.DisableCursor()
+						scrollableWidget = page.ProcTable
+						scrollableWidget.EnableCursor()
+					}
+				}
+
+			case "<Tab>":
+				if utilitySelected == core.None && eventsShown {
+					scrollableWidget.DisableCursor()
+					if scrollableWidget == page.ProcTable {
+						scrollableWidget = page.EventsTable
 					} else {
-						errorBox.SetErrorString(fmt.Sprintf("Process not found: %d", pidToKill), err)
-						utilitySelected = core.Error
+						scrollableWidget = page.ProcTable
 					}
+					scrollableWidget.EnableCursor()
+				}
 
-					runAllProc = true
-					utilitySelected = core.None
-					updateProcs()
+			// handle tree view
+			case "t":
+				if utilitySelected == core.None {
+					treeMode = !treeMode
+					setTitle()
+					setRows()
 				}
 
-				scrollableWidget = page.ProcTable
-				scrollableWidget.EnableCursor()
+			case "-", "+":
+				if utilitySelected == core.None && treeMode && page.ProcTable.SelectedRow < len(rowPIDs) {
+					pid := rowPIDs[page.ProcTable.SelectedRow]
+					collapsed[pid] = e.ID == "-"
+					setRows()
+				}
+
+			// handle marking of processes for bulk actions
+			case "<Space>":
+				if utilitySelected == core.None && !guard.ReadOnly() && page.ProcTable.SelectedRow < len(rowPIDs) {
+					bulk.toggle(procKeys(shown)[rowPIDs[page.ProcTable.SelectedRow]])
+					setTitle()
+					setRows()
+					page.ProcTable.ScrollDown()
+				}
+
+			case "A":
+				if utilitySelected == core.None && !guard.ReadOnly() {
+					bulk.markAll(visibleProcs())
+					setTitle()
+					setRows()
+				}
+
+			case "U":
+				if utilitySelected == core.None {
+					bulk.unmarkAll()
+					setTitle()
+					setRows()
+				}
+
+			case "c":
+				if utilitySelected == core.None {
+					openCompare()
+				}
+
+			case "<Enter>":
+				if utilitySelected == core.Action {
+					utilitySelected = bulk.openPrompt(shown)
+					break
+				}
+				if utilitySelected == core.Kill {
+					utilitySelected = bulk.confirmSelectedSignal()
+					break
+				}
+				if utilitySelected == core.None && groupIdx != -1 && !inGroup && page.ProcTable.SelectedRow < len(rowKeys) {
+					// show the members of the selected group
+					groupKey = rowKeys[page.ProcTable.SelectedRow]
+					inGroup = true
+					setView()
+				}
 			}
 
 			updateUI()
@@ -363,34 +706,51 @@ func AllProcVisuals(ctx context.Context, dataChannel chan []*proc.Process, refre
 				previousKey = e.ID
 			}
 
-		case data := <-dataChannel:
+		case procs = <-dataChannel:
 			if runAllProc {
 				page.ProcTable.CursorColor = selectedStyle
-				procData := getData(data)
-				page.ProcTable.Rows = procData
-				if sortIdx != -1 {
-					utils.SortData(page.ProcTable.Rows, sortIdx, sortAsc, "PROCS")
-				}
+				updateProcs()
 				on.Do(updateUI)
 			}
 
+		case data := <-cmpChannel:
+			// the history is recorded while paused, only the page is frozen.
+			cmp.record(data, time.Now())
+			if runAllProc {
+				cmp.draw()
+			}
+
 		case <-tick: // Update page with new values
-			if utilitySelected == core.Kill {
-				exists, _ := proc.PidExists(pidToKill)
-				if !exists {
-					runAllProc = true
+			switch utilitySelected {
+			case core.Kill, core.Action, core.Prompt:
+				// close once the processes acted on have exited
+				if !bulk.running(utilitySelected) {
 					utilitySelected = core.None
-					updateProcs()
+					closeAction()
+					updateUI()
 				}
-			} else {
+			default:
 				page.ProcTable.CursorColor = selectedStyle
 			}
 
-			if utilitySelected != core.Help {
-				if utilitySelected == core.Kill {
-					ui.Render(signals)
+			if utilitySelected == core.Compare {
+				ui.Render(cmp.page.Grid)
+				break
+			}
+			if utilitySelected != core.Help && utilitySelected != core.Error && utilitySelected != core.Confirm {
+				switch utilitySelected {
+				case core.Kill:
+					ui.Render(bulk.signals)
+				case core.Action:
+					ui.Render(bulk.actions)
 				}
 				ui.Render(page.Grid)
+				switch utilitySelected {
+				case core.Filter:
+					ui.Render(page.FilterBox)
+				case core.Prompt:
+					ui.Render(page.PromptBox)
+				}
 			}
 		}
 	}
@@ -0,0 +1,395 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/sink/tui/misc"
+	proc "github.com/shirou/gopsutil/process"
+)
+
+// containsKey returns whether a process key is in a list of keys.
+func containsKey(keys []process.Key, key process.Key) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// procNames returns the command name of every process by PID.
+func procNames(procs []process.Snapshot) map[int32]string {
+	names := make(map[int32]string, len(procs))
+	for _, p := range procs {
+		names[p.PID] = p.Name
+	}
+	return names
+}
+
+// sameProcess returns an error wrapping core.ErrInvalidPID if the process
+// running with the PID of the key is not the process it identifies, as
+// the PID was reused after that process exited.
+func sameProcess(key process.Key) error {
+	createTime, err := process.CreateTime(key.PID)
+	if err != nil {
+		return err
+	}
+	if createTime != key.CreateTime {
+		return fmt.Errorf("%w: PID %d was reused by another process", core.ErrInvalidPID, key.PID)
+	}
+	return nil
+}
+
+// bulkActions keeps the processes marked for a bulk action, and the
+// processes a signal or an action is chosen for until it is performed.
+// Its methods handle the keys of the signal selector, the action prompt
+// and the confirmation, and return the utility to show next. Signals and
+// actions are refused by the guard for protected processes.
+type bulkActions struct {
+	guard      *process.Guard
+	signals    *misc.SignalTable
+	actions    *misc.ActionTable
+	confirmBox *misc.ConfirmBox
+	promptBox  *widgets.Paragraph
+	errorBox   *misc.ErrorBox
+
+	// processes marked for a bulk action, by PID and start time so that
+	// a mark does not carry over to a process reusing the PID
+	marked map[process.Key]bool
+
+	// processes selected for a signal along with their command names by
+	// PID, and the signal to confirm
+	toKill  []process.Key
+	names   map[int32]string
+	sig     syscall.Signal
+	sigName string
+
+	// the process an action is performed on, the action and the value typed
+	toAct      process.Key
+	action     procAction
+	actionName string
+	promptText string
+}
+
+// newBulkActions returns bulkActions without marked processes, prompting
+// for the value of an action in promptBox and reporting failures in
+// errorBox.
+func newBulkActions(guard *process.Guard, promptBox *widgets.Paragraph, errorBox *misc.ErrorBox) *bulkActions {
+	return &bulkActions{
+		guard:      guard,
+		signals:    misc.NewSignalTable(),
+		actions:    misc.NewActionTable().ForCommand(misc.ProcCommand),
+		confirmBox: misc.NewConfirmBox(),
+		promptBox:  promptBox,
+		errorBox:   errorBox,
+		marked:     make(map[process.Key]bool),
+	}
+}
+
+// toggle marks the process with the given key, or unmarks it if it is
+// marked.
+func (b *bulkActions) toggle(key process.Key) {
+	if b.marked[key] {
+		delete(b.marked, key)
+	} else {
+		b.marked[key] = true
+	}
+}
+
+// markAll marks the given processes.
+func (b *bulkActions) markAll(procs []process.Snapshot) {
+	for _, p := range procs {
+		b.marked[p.Key()] = true
+	}
+}
+
+// unmarkAll unmarks every process.
+func (b *bulkActions) unmarkAll() {
+	b.marked = make(map[process.Key]bool)
+}
+
+// forgetExited unmarks the processes that are not among the running ones.
+func (b *bulkActions) forgetExited(running []process.Snapshot) {
+	if len(b.marked) == 0 {
+		return
+	}
+	keys := make(map[process.Key]bool, len(running))
+	for _, p := range running {
+		keys[p.Key()] = true
+	}
+	for key := range b.marked {
+		if !keys[key] {
+			delete(b.marked, key)
+		}
+	}
+}
+
+// markedRows returns the rows of the table showing a marked process, given
+// the PID of the process on every row.
+func (b *bulkActions) markedRows(rowPIDs []int32, keys map[int32]process.Key) map[int]bool {
+	rows := make(map[int]bool)
+	for i, pid := range rowPIDs {
+		if b.marked[keys[pid]] {
+			rows[i] = true
+		}
+	}
+	return rows
+}
+
+// markedPIDs returns the PIDs of the marked processes.
+func (b *bulkActions) markedPIDs() []int32 {
+	pids := make([]int32, 0, len(b.marked))
+	for key := range b.marked {
+		pids = append(pids, key.PID)
+	}
+	return pids
+}
+
+// openKill selects the processes to send a signal to among those shown,
+// the marked processes in the order of the rows or else the process on
+// the selected row, and returns core.Kill to choose the signal. A selected
+// process that is protected is refused right away with core.Error, and
+// core.None is returned if there is nothing to select.
+func (b *bulkActions) openKill(shown []process.Snapshot, rowPIDs []int32, selected int) core.Utility {
+	if len(b.marked) == 0 && selected >= len(rowPIDs) {
+		return core.None
+	}
+	keys := procKeys(shown)
+	b.names = procNames(shown)
+	b.toKill = b.toKill[:0]
+	for _, pid := range rowPIDs {
+		if b.marked[keys[pid]] {
+			b.toKill = append(b.toKill, keys[pid])
+		}
+	}
+	if len(b.toKill) < len(b.marked) {
+		// marked processes hidden by the filter or a collapsed subtree
+		for key := range b.marked {
+			if !containsKey(b.toKill, key) {
+				b.toKill = append(b.toKill, key)
+			}
+		}
+	}
+	if len(b.toKill) == 0 {
+		pid := rowPIDs[selected]
+		if err := b.guard.CheckRunning(pid); err != nil {
+			b.errorBox.SetErrorString(fmt.Sprintf("Cannot kill process: %d", pid), err)
+			return core.Error
+		}
+		b.toKill = append(b.toKill, keys[pid])
+	}
+	return core.Kill
+}
+
+// scrollToSignal moves the signal selector to the signal numbered by the
+// key pressed. Double digit numbers are handled by checking the previous
+// key and, if it is among 1, 2 and 3, moving to the corresponding double
+// digit number (as there are currently 31 supported signals). For example,
+// pressing 25 first moves to signal 2, then to signal 25. It returns
+// whether the previous key was used.
+func (b *bulkActions) scrollToSignal(key, previousKey string) bool {
+	idx, _ := strconv.Atoi(key)
+	usedPrevious := false
+	if previousKey == "1" || previousKey == "2" || previousKey == "3" {
+		prevIdx, _ := strconv.Atoi(previousKey)
+		idx = 10*prevIdx + idx
+		usedPrevious = true
+	}
+	b.signals.Table.ScrollToIndex(idx - 1) // account for 0-indexing
+	return usedPrevious
+}
+
+// confirmSelectedSignal asks for confirmation of the signal selected in
+// the signal selector, and returns core.Confirm.
+func (b *bulkActions) confirmSelectedSignal() core.Utility {
+	return b.confirmKill(b.signals.SelectedSignal(), b.signals.Rows[b.signals.SelectedRow][1])
+}
+
+// confirmKill asks for confirmation before sending a signal to the
+// processes selected for killing, noting those that are protected, and
+// returns core.Confirm.
+func (b *bulkActions) confirmKill(sig syscall.Signal, sigName string) core.Utility {
+	targets := make([]string, 0, len(b.toKill))
+	for _, key := range b.toKill {
+		target := fmt.Sprintf("%d (%s)", key.PID, b.names[key.PID])
+		if err := b.guard.CheckRunning(key.PID); err != nil {
+			target += " will be skipped: " + err.Error()
+		}
+		targets = append(targets, target)
+	}
+
+	question := fmt.Sprintf("Send %s to this process?", sigName)
+	if len(b.toKill) > 1 {
+		question = fmt.Sprintf("Send %s to these %d processes?", sigName, len(b.toKill))
+	}
+	b.confirmBox.SetQuestion(question, targets)
+	b.sig, b.sigName = sig, sigName
+	return core.Confirm
+}
+
+// answer handles a key pressed while a signal is to be confirmed, and
+// returns the utility to show next. The signal is sent on "y", after which
+// core.Error is returned if the error box is to be shown.
+func (b *bulkActions) answer(key string) core.Utility {
+	switch key {
+	case "y":
+		return b.kill()
+	case "n", "<Escape>":
+		return core.None
+	}
+	return core.Confirm
+}
+
+// kill sends the confirmed signal to the processes selected for killing.
+// A failure is shown in the error box, or the outcome for every process if
+// several processes were selected, and core.Error is returned.
+func (b *bulkActions) kill() core.Utility {
+	next := core.None
+	results := make([]misc.ActionResult, 0, len(b.toKill))
+	for _, key := range b.toKill {
+		pid := key.PID
+		procToKill, err := proc.NewProcess(pid)
+		if err == nil {
+			// the process may have exited since it was selected
+			err = sameProcess(key)
+		}
+		if err != nil {
+			if len(b.toKill) == 1 {
+				b.errorBox.SetErrorString(fmt.Sprintf("Process not found: %d", pid), err)
+				next = core.Error
+			}
+		} else if err = b.guard.CheckRunning(pid); err != nil {
+			// the guard matches the names read just before the
+			// signal, not the command name of the snapshot
+			if len(b.toKill) == 1 {
+				b.errorBox.SetErrorString(fmt.Sprintf("Cannot kill process: %d", pid), err)
+				next = core.Error
+			}
+		} else {
+			err = procToKill.SendSignal(b.sig)
+			if err != nil && len(b.toKill) == 1 {
+				b.errorBox.SetErrorString(fmt.Sprintf("Error killing process: %d", pid), err)
+				next = core.Error
+			}
+		}
+		results = append(results, misc.ActionResult{
+			Target: fmt.Sprintf("%d (%s)", pid, b.names[pid]),
+			Err:    err,
+		})
+	}
+	if len(b.toKill) > 1 {
+		b.errorBox.SetResults("Sent "+b.sigName, results)
+		next = core.Error
+	}
+	return next
+}
+
+// openAction selects the process with the given key to act on, and
+// returns core.Action to choose the action. A protected process is
+// refused right away with core.Error.
+func (b *bulkActions) openAction(key process.Key) core.Utility {
+	if err := b.guard.CheckRunning(key.PID); err != nil {
+		b.errorBox.SetErrorString(fmt.Sprintf("Cannot act on process: %d", key.PID), err)
+		return core.Error
+	}
+	b.toAct = key
+	return core.Action
+}
+
+// openPrompt opens the prompt of the action selected in the action
+// selector with the current setting of the process among those shown, and
+// returns core.Prompt.
+func (b *bulkActions) openPrompt(shown []process.Snapshot) core.Utility {
+	b.actionName = b.actions.SelectedAction()
+	b.action = procActions[b.actionName]
+	b.promptText = ""
+	for _, p := range shown {
+		if p.Key() == b.toAct {
+			b.promptText = b.action.current(p)
+			break
+		}
+	}
+	b.setPrompt(nil)
+	return core.Prompt
+}
+
+// setPrompt shows the value typed in the prompt, and the error of an
+// invalid value in its title.
+func (b *bulkActions) setPrompt(err error) {
+	b.promptBox.Text = b.promptText
+	b.promptBox.Title = fmt.Sprintf(" %s PID %d: %s ", b.actionName, b.toAct.PID, b.action.prompt)
+	if err != nil {
+		b.promptBox.Title = fmt.Sprintf(" %s PID %d: %s ", b.actionName, b.toAct.PID, err)
+	}
+}
+
+// editPrompt handles a key pressed in the action prompt, whose value is
+// validated as it is typed and applied on <Enter>, and returns the utility
+// to show next. A failed action returns core.Error to show the error box.
+func (b *bulkActions) editPrompt(key string) core.Utility {
+	switch key {
+	case "<Escape>":
+		return core.None
+	case "<Enter>":
+		if err := b.action.validate(b.promptText); err != nil {
+			b.setPrompt(err)
+			return core.Prompt
+		}
+		// the process may have exited and its PID been reused since the
+		// action was opened
+		err := sameProcess(b.toAct)
+		if err == nil {
+			err = b.guard.CheckRunning(b.toAct.PID)
+		}
+		if err == nil {
+			err = b.action.apply(b.toAct.PID, b.promptText)
+		}
+		if err != nil {
+			b.errorBox.SetErrorString(fmt.Sprintf("%s: %d", b.action.failure, b.toAct.PID), err)
+			return core.Error
+		}
+		return core.None
+	default:
+		b.promptText = editInput(b.promptText, key)
+		b.setPrompt(b.action.validate(b.promptText))
+		return core.Prompt
+	}
+}
+
+// running reports whether any of the processes the given utility is
+// performed on is still running, so that it is closed once they all exited.
+func (b *bulkActions) running(utility core.Utility) bool {
+	switch utility {
+	case core.Kill:
+		for _, key := range b.toKill {
+			if sameProcess(key) == nil {
+				return true
+			}
+		}
+		return false
+	case core.Action, core.Prompt:
+		return sameProcess(b.toAct) == nil
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/sink/tui/misc"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestSameProcess(t *testing.T) {
+	pid := int32(os.Getpid())
+	createTime, err := process.CreateTime(pid)
+	utils.Raises(t, err)
+	utils.Raises(t, sameProcess(process.Key{PID: pid, CreateTime: createTime}))
+
+	// a process that started at another time has exited and its PID
+	// was reused.
+	err = sameProcess(process.Key{PID: pid, CreateTime: createTime - 1000})
+	utils.Assert(t, errors.Is(err, core.ErrInvalidPID), "expected a reused PID to be refused, got %v", err)
+}
+
+func newTestBulkActions(t *testing.T) *bulkActions {
+	guard, err := process.NewGuard(nil, false)
+	utils.Raises(t, err)
+	return newBulkActions(guard, widgets.NewParagraph(), misc.NewErrorBox())
+}
+
+func TestBulkActionsMarks(t *testing.T) {
+	procs := []process.Snapshot{
+		{PID: 10, CreateTime: 1000},
+		{PID: 20, CreateTime: 2000},
+		{PID: 30, CreateTime: 3000},
+	}
+	rowPIDs := []int32{30, 20, 10}
+
+	b := newTestBulkActions(t)
+	b.toggle(procs[0].Key())
+	b.toggle(procs[1].Key())
+	b.toggle(procs[1].Key())
+	utils.Equals(t, []int32{10}, b.markedPIDs())
+	utils.Equals(t, map[int]bool{2: true}, b.markedRows(rowPIDs, procKeys(procs)))
+
+	// a mark does not carry over to a process reusing the PID.
+	reused := []process.Snapshot{{PID: 10, CreateTime: 4000}, procs[1], procs[2]}
+	utils.Equals(t, map[int]bool{}, b.markedRows(rowPIDs, procKeys(reused)))
+	b.forgetExited(reused)
+	utils.Equals(t, 0, len(b.marked))
+
+	b.markAll(procs)
+	utils.Equals(t, 3, len(b.marked))
+	b.unmarkAll()
+	utils.Equals(t, 0, len(b.marked))
+}
+
+func TestBulkActionsOpenKill(t *testing.T) {
+	procs := []process.Snapshot{
+		{PID: 10, Name: "make", CreateTime: 1000},
+		{PID: 20, Name: "cc1", CreateTime: 2000},
+		{PID: 30, Name: "ld", CreateTime: 3000},
+	}
+
+	// the marked processes are selected in the order of the rows, then
+	// those that are not shown.
+	b := newTestBulkActions(t)
+	b.markAll(procs[:2])
+	utils.Equals(t, core.Kill, b.openKill(procs, []int32{20, 30}, 1))
+	utils.Equals(t, []process.Key{procs[1].Key(), procs[0].Key()}, b.toKill)
+
+	utils.Equals(t, core.Confirm, b.confirmKill(syscall.SIGTERM, "SIGTERM"))
+	utils.Equals(t, core.Confirm, b.answer("x"))
+	utils.Equals(t, core.None, b.answer("n"))
+
+	// without marks the process on the selected row is selected, unless
+	// it is protected.
+	b.unmarkAll()
+	utils.Equals(t, core.Kill, b.openKill(procs, []int32{20, 30}, 1))
+	utils.Equals(t, []process.Key{procs[2].Key()}, b.toKill)
+	utils.Equals(t, core.None, b.openKill(procs, []int32{20, 30}, 2))
+
+	self := process.Snapshot{PID: int32(os.Getpid()), Name: "grofer"}
+	utils.Equals(t, core.Error, b.openKill([]process.Snapshot{self}, []int32{self.PID}, 0))
+}
+
+func TestBulkActionsPrompt(t *testing.T) {
+	b := newTestBulkActions(t)
+	b.toAct = process.Key{PID: 42, CreateTime: 1000}
+	b.actionName = "RENICE"
+	b.action = procActions[b.actionName]
+
+	// an invalid value is reported in the title and not applied.
+	utils.Equals(t, core.Prompt, b.editPrompt("x"))
+	utils.Equals(t, "x", b.promptBox.Text)
+	utils.Equals(t, core.Prompt, b.editPrompt("<Enter>"))
+	utils.Assert(t, strings.HasPrefix(b.promptBox.Title, " RENICE PID 42: "), "unexpected title %q", b.promptBox.Title)
+	utils.Equals(t, core.None, b.editPrompt("<Escape>"))
+}
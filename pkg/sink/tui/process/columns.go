@@ -0,0 +1,323 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+// column describes a column of the process table.
+type column struct {
+	title string
+	// width is the width of the column, or the minimum width of
+	// a flexible column. Flexible columns share the space left.
+	width int
+	flex  bool
+	// indent is set for the columns that are indented in the tree view.
+	indent bool
+	value  func(p process.Snapshot) string
+	less   func(a, b process.Snapshot) bool
+	// unset reports whether the value of a process is not known, if set.
+	// Those processes sort below the others in either direction.
+	unset func(p process.Snapshot) bool
+}
+
+// byNumber returns a less function comparing processes by a numeric field.
+func byNumber(field func(p process.Snapshot) float64) func(a, b process.Snapshot) bool {
+	return func(a, b process.Snapshot) bool {
+		return field(a) < field(b)
+	}
+}
+
+// unreadableIO reports whether the I/O counters of a process are not
+// readable.
+func unreadableIO(p process.Snapshot) bool {
+	return !p.IOReadable
+}
+
+// ioValue returns the value of an I/O column, "-" for the processes whose
+// counters are not readable rather than zero.
+func ioValue(value func(p process.Snapshot) string) func(p process.Snapshot) string {
+	return func(p process.Snapshot) string {
+		if !p.IOReadable {
+			return "-"
+		}
+		return value(p)
+	}
+}
+
+// byString returns a less function comparing processes by a text field.
+func byString(field func(p process.Snapshot) string) func(a, b process.Snapshot) bool {
+	return func(a, b process.Snapshot) bool {
+		return field(a) < field(b)
+	}
+}
+
+// columns holds every column the process table can show keyed by the name
+// used to configure it.
+var columns = map[string]column{
+	"pid": {
+		title: "PID",
+		width: 10,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%d", p.PID) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.PID) }),
+	},
+	"ppid": {
+		title: "PPID",
+		width: 10,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%d", p.PPID) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.PPID) }),
+	},
+	"user": {
+		title: "User",
+		width: 12,
+		value: func(p process.Snapshot) string { return p.Username },
+		less:  byString(func(p process.Snapshot) string { return p.Username }),
+	},
+	"command": {
+		title:  "Command",
+		width:  40,
+		flex:   true,
+		indent: true,
+		value:  func(p process.Snapshot) string { return p.Name },
+		less:   byString(func(p process.Snapshot) string { return p.Name }),
+	},
+	"cmdline": {
+		title:  "Command Line",
+		width:  40,
+		flex:   true,
+		indent: true,
+		value:  func(p process.Snapshot) string { return p.Cmdline },
+		less:   byString(func(p process.Snapshot) string { return p.Cmdline }),
+	},
+	"cpu": {
+		title: "CPU",
+		width: 10,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%.2f%%", p.CPUPercent) },
+		less:  byNumber(func(p process.Snapshot) float64 { return p.CPUPercent }),
+	},
+	"mem": {
+		title: "Memory",
+		width: 10,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%.2f%%", p.MemoryPercent) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.MemoryPercent) }),
+	},
+	"rss": {
+		title: "RSS",
+		width: 10,
+		value: func(p process.Snapshot) string { return formatBytes(p.RSS) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.RSS) }),
+	},
+	"vms": {
+		title: "Virtual",
+		width: 10,
+		value: func(p process.Snapshot) string { return formatBytes(p.VMS) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.VMS) }),
+	},
+	"cgroup": {
+		title: "Cgroup",
+		width: 30,
+		flex:  true,
+		value: func(p process.Snapshot) string { return p.Cgroup },
+		less:  byString(func(p process.Snapshot) string { return p.Cgroup }),
+	},
+	"status": {
+		title: "Status",
+		width: 8,
+		value: func(p process.Snapshot) string { return p.Status },
+		less:  byString(func(p process.Snapshot) string { return p.Status }),
+	},
+	"nice": {
+		title: "Nice",
+		width: 6,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%d", p.Nice) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.Nice) }),
+	},
+	"foreground": {
+		title: "Foreground",
+		width: 12,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%t", p.Foreground) },
+		less:  byString(func(p process.Snapshot) string { return fmt.Sprintf("%t", p.Foreground) }),
+	},
+	"created": {
+		title: "Creation Time",
+		width: 25,
+		value: func(p process.Snapshot) string { return utils.GetDateFromUnix(p.CreateTime) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.CreateTime) }),
+	},
+	"elapsed": {
+		title: "Elapsed",
+		width: 18,
+		value: func(p process.Snapshot) string {
+			elapsed := time.Since(time.Unix(0, p.CreateTime*int64(time.Millisecond)))
+			return utils.SecondsToHuman(int(elapsed.Seconds()))
+		},
+		less: byNumber(func(p process.Snapshot) float64 { return -float64(p.CreateTime) }),
+	},
+	"threads": {
+		title: "Thread Count",
+		width: 15,
+		value: func(p process.Snapshot) string { return fmt.Sprintf("%d", p.NumThreads) },
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.NumThreads) }),
+	},
+	"read": {
+		title: "Read",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return formatBytes(p.IO.ReadBytes) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.IO.ReadBytes) }),
+		unset: unreadableIO,
+	},
+	"write": {
+		title: "Write",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return formatBytes(p.IO.WriteBytes) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return float64(p.IO.WriteBytes) }),
+		unset: unreadableIO,
+	},
+	"read/s": {
+		title: "Read/s",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return formatByteRate(p.IORate.ReadBytes) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return p.IORate.ReadBytes }),
+		unset: unreadableIO,
+	},
+	"write/s": {
+		title: "Write/s",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return formatByteRate(p.IORate.WriteBytes) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return p.IORate.WriteBytes }),
+		unset: unreadableIO,
+	},
+	"rchar/s": {
+		title: "RChar/s",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return formatByteRate(p.IORate.RChar) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return p.IORate.RChar }),
+		unset: unreadableIO,
+	},
+	"wchar/s": {
+		title: "WChar/s",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return formatByteRate(p.IORate.WChar) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return p.IORate.WChar }),
+		unset: unreadableIO,
+	},
+	"syscr/s": {
+		title: "SyscR/s",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return fmt.Sprintf("%.0f", p.IORate.SyscR) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return p.IORate.SyscR }),
+		unset: unreadableIO,
+	},
+	"syscw/s": {
+		title: "SyscW/s",
+		width: 10,
+		value: ioValue(func(p process.Snapshot) string { return fmt.Sprintf("%.0f", p.IORate.SyscW) }),
+		less:  byNumber(func(p process.Snapshot) float64 { return p.IORate.SyscW }),
+		unset: unreadableIO,
+	},
+}
+
+// DefaultColumns are the columns of the process table if none are configured.
+var DefaultColumns = []string{"pid", "command", "cpu", "mem", "status", "foreground", "created", "threads"}
+
+// ColumnNames returns the names of all the columns of the process table.
+func ColumnNames() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getColumns returns the columns with the given names in order, or the
+// default columns if no names are given. It returns an error wrapping
+// core.ErrUnknownColumn if a name is not a column.
+func getColumns(names []string) ([]column, error) {
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+
+	cols := make([]column, 0, len(names))
+	for _, name := range names {
+		col, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", core.ErrUnknownColumn, name)
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// CheckColumns returns an error wrapping core.ErrUnknownColumn if a name is
+// not a column of the process table.
+func CheckColumns(names []string) error {
+	_, err := getColumns(names)
+	return err
+}
+
+// getRow returns the values of the columns for a process.
+func getRow(p process.Snapshot, cols []column) []string {
+	row := make([]string, len(cols))
+	for i, col := range cols {
+		row[i] = col.value(p)
+	}
+	return row
+}
+
+// sortProcs sorts processes by the given column, placing the processes
+// whose value is not known last.
+func sortProcs(procs []process.Snapshot, col column, asc bool) {
+	sort.SliceStable(procs, func(i, j int) bool {
+		if col.unset != nil {
+			if unsetI, unsetJ := col.unset(procs[i]), col.unset(procs[j]); unsetI != unsetJ {
+				return unsetJ
+			}
+		}
+		if asc {
+			return col.less(procs[i], procs[j])
+		}
+		return col.less(procs[j], procs[i])
+	})
+}
+
+// formatBytes formats an amount of bytes in the largest binary unit it
+// has at least one of.
+func formatBytes(bytes uint64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1fG", utils.RoundUint(bytes, "G", 1))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1fM", utils.RoundUint(bytes, "M", 1))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1fK", utils.RoundUint(bytes, "K", 1))
+	default:
+		return fmt.Sprintf("%dB", bytes)
+	}
+}
+
+// formatByteRate formats an amount of bytes per second.
+func formatByteRate(rate float64) string {
+	return formatBytes(uint64(rate)) + "/s"
+}
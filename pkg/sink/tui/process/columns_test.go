@@ -0,0 +1,78 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGetColumns(t *testing.T) {
+	cols, err := getColumns([]string{"user", "pid", "rss"})
+	utils.Raises(t, err)
+	utils.Equals(t, 3, len(cols))
+
+	row := getRow(process.Snapshot{PID: 42, Username: "alice", RSS: 3 << 20}, cols)
+	utils.Equals(t, []string{"alice", "42", "3.0M"}, row)
+
+	// I/O counters that are not readable are not shown as zero.
+	cols, err = getColumns([]string{"read", "write/s"})
+	utils.Raises(t, err)
+	utils.Equals(t, []string{"-", "-"}, getRow(process.Snapshot{PID: 42}, cols))
+	utils.Equals(t, []string{"2.0K", "0B/s"}, getRow(process.Snapshot{PID: 42, IO: process.IOStat{ReadBytes: 2048}, IOReadable: true}, cols))
+
+	_, err = getColumns([]string{"pid", "bogus"})
+	utils.Assert(t, errors.Is(err, core.ErrUnknownColumn), "expected ErrUnknownColumn, got %v", err)
+}
+
+func TestSortProcsByColumn(t *testing.T) {
+	procs := []process.Snapshot{
+		{PID: 2, RSS: 2048, Name: "b"},
+		{PID: 10, RSS: 512, Name: "c"},
+		{PID: 1, RSS: 4096, Name: "a"},
+	}
+	pids := func() []int32 {
+		sorted := []int32{}
+		for _, p := range procs {
+			sorted = append(sorted, p.PID)
+		}
+		return sorted
+	}
+
+	// PIDs sort numerically rather than as text.
+	sortProcs(procs, columns["pid"], true)
+	utils.Equals(t, []int32{1, 2, 10}, pids())
+
+	sortProcs(procs, columns["rss"], false)
+	utils.Equals(t, []int32{1, 2, 10}, pids())
+
+	sortProcs(procs, columns["command"], false)
+	utils.Equals(t, []int32{10, 2, 1}, pids())
+
+	// processes whose I/O counters are not readable sort last in either
+	// direction.
+	procs[0].IOReadable, procs[2].IOReadable = true, true
+	procs[2].IO.ReadBytes = 512
+	sortProcs(procs, columns["read"], true)
+	utils.Equals(t, []int32{10, 1, 2}, pids())
+	sortProcs(procs, columns["read"], false)
+	utils.Equals(t, []int32{1, 10, 2}, pids())
+}
@@ -0,0 +1,303 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/pesos/grofer/pkg/core"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/sink/tui/misc"
+	"github.com/pesos/grofer/pkg/utils"
+	viz "github.com/pesos/grofer/pkg/utils/visualization"
+)
+
+// MaxCompared is the number of processes that can be compared at once, one
+// for every colour of the comparison graphs.
+const MaxCompared = 6
+
+// compareLabelWidth is the width of the column naming the metrics in the
+// comparison table.
+const compareLabelWidth = 22
+
+// compareColors are the colours of the processes compared, in order.
+var compareColors = [MaxCompared]ui.Color{
+	ui.ColorGreen,
+	ui.ColorMagenta,
+	ui.ColorYellow,
+	ui.ColorCyan,
+	ui.ColorRed,
+	ui.ColorBlue,
+}
+
+// getCompareStatus returns the status of a compared process.
+func getCompareStatus(p *process.Process) string {
+	if !p.IsRunning || p.Status == "Z" {
+		return "Exited"
+	}
+	if name, ok := statusMap[p.Status]; ok {
+		return name + " (" + p.Status + ")"
+	}
+	return p.Status
+}
+
+// getCompareRate returns the latest rate of a series, or "-" while there
+// is none.
+func getCompareRate(s series) string {
+	if len(s.data) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", s.last())
+}
+
+// getCompareRows returns the rows of the comparison table, with a column
+// for every process in the colour of its graphs.
+func getCompareRows(procs []*process.Process, histories map[int32]*procHistory) [][]string {
+	rows := [][]string{
+		{"PID"},
+		{"Name"},
+		{"Status"},
+		{"CPU %"},
+		{"Mem %"},
+		{"RSS"},
+		{"Threads"},
+		{"Ctx switches (vol/inv)"},
+		{"Ctx switches/s"},
+		{"Page faults (min/maj)"},
+		{"Page faults/s"},
+		{"Disk read/s"},
+		{"Disk write/s"},
+		{"Creation Time"},
+	}
+
+	for i, p := range procs {
+		h, ok := histories[p.Proc.Pid]
+		if !ok {
+			h = &procHistory{}
+		}
+		rss, switches, faults, read, write := "-", "-", "-", "-", "-"
+		if p.MemoryInfo != nil {
+			rss = formatBytes(p.MemoryInfo.RSS)
+		}
+		if p.NumCtxSwitches != nil {
+			switches = fmt.Sprintf("%d / %d", p.NumCtxSwitches.Voluntary, p.NumCtxSwitches.Involuntary)
+		}
+		if p.PageFault != nil {
+			faults = fmt.Sprintf("%d / %d", p.PageFault.MinorFaults, p.PageFault.MajorFaults)
+		}
+		if p.IO != nil {
+			read, write = formatByteRate(p.IORate.ReadBytes), formatByteRate(p.IORate.WriteBytes)
+		}
+
+		values := []string{
+			fmt.Sprintf("[%d](fg:%s,mod:bold)", p.Proc.Pid, colorName(compareColors[i%MaxCompared])),
+			p.Name,
+			getCompareStatus(p),
+			fmt.Sprintf("%.2f", p.CPUPercent),
+			fmt.Sprintf("%.2f", p.MemoryPercent),
+			rss,
+			strconv.Itoa(int(p.NumThreads)),
+			switches,
+			getCompareRate(h.ctxSwitches),
+			faults,
+			getCompareRate(h.pageFaults),
+			read,
+			write,
+			utils.GetDateFromUnix(p.CreateTime),
+		}
+		for j, value := range values {
+			rows[j] = append(rows[j], value)
+		}
+	}
+	return rows
+}
+
+// colorName returns the name of a colour in the style of a termui row.
+func colorName(color ui.Color) string {
+	for name, c := range ui.StyleParserColorMap {
+		if c == color {
+			return name
+		}
+	}
+	return "clear"
+}
+
+// comparison keeps the history of the processes compared and draws it.
+type comparison struct {
+	page      *comparePage
+	histories map[int32]*procHistory
+	latest    []*process.Process // latest sample recorded.
+}
+
+// newComparison returns a comparison with an empty history.
+func newComparison() *comparison {
+	return &comparison{
+		page:      newComparePage(),
+		histories: make(map[int32]*procHistory),
+	}
+}
+
+// record records a sample of the processes taken at the given time,
+// without updating the page so that it can stay frozen while paused.
+func (c *comparison) record(procs []*process.Process, now time.Time) {
+	for _, p := range procs {
+		h, ok := c.histories[p.Proc.Pid]
+		if !ok {
+			h = &procHistory{}
+			c.histories[p.Proc.Pid] = h
+		}
+		h.add(p, now)
+	}
+	c.latest = procs
+}
+
+// draw updates the page with the history recorded so far.
+func (c *comparison) draw() {
+	for i, p := range c.latest {
+		h := c.histories[p.Proc.Pid]
+		key := strconv.Itoa(int(p.Proc.Pid))
+		color := compareColors[i%MaxCompared]
+		setSeries := func(graph *viz.LineGraph, s *series, value string) {
+			graph.Data[key] = s.data
+			graph.Labels[key] = value
+			graph.LineColors[key] = color
+		}
+		setSeries(c.page.CPUGraph, &h.cpu, fmt.Sprintf("%.2f%%", h.cpu.last()))
+		setSeries(c.page.RSSGraph, &h.rss, formatBytes(uint64(h.rss.last())))
+		setSeries(c.page.ThreadsGraph, &h.threads, fmt.Sprintf("%.0f", h.threads.last()))
+		setSeries(c.page.CTXSwitchesGraph, &h.ctxSwitches, getCompareRate(h.ctxSwitches))
+		setSeries(c.page.PageFaultsGraph, &h.pageFaults, getCompareRate(h.pageFaults))
+	}
+	c.page.Table.Rows = getCompareRows(c.latest, c.histories)
+}
+
+// watchProcs sends the metrics of the processes with the given PIDs on the
+// data channel at the refresh rate until the context is done. Processes
+// that do not exist are left out.
+func watchProcs(ctx context.Context, pids []int32, refreshRate uint64, dataChannel chan<- []*process.Process) error {
+	procs := make([]*process.Process, 0, len(pids))
+	for _, pid := range pids {
+		if p, err := process.NewProcess(pid); err == nil {
+			procs = append(procs, p)
+		}
+	}
+
+	return utils.TickUntilDone(ctx, refreshRate, func() error {
+		// send copies so that the page does not race with the next update.
+		copies := make([]*process.Process, 0, len(procs))
+		for _, p := range procs {
+			p.UpdateProcInfo()
+			copies = append(copies, p.Copy())
+		}
+		select {
+		case dataChannel <- copies:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// CompareVisuals renders the metrics of the processes received side by
+// side and on shared graphs, with one colour for every process.
+func CompareVisuals(ctx context.Context, dataChannel chan []*process.Process, refreshRate uint64) error {
+	defer ui.Close()
+
+	if err := ui.Init(); err != nil {
+		log.Fatalf("failed to initialize termui: %v", err)
+	}
+
+	var on sync.Once
+	var help *misc.HelpMenu = misc.NewHelpMenu().ForCommand(misc.CompareProcCommand)
+
+	cmp := newComparison()
+	utilitySelected := core.None
+
+	// variables to pause UI render
+	runProc := true
+	pause := func() {
+		runProc = !runProc
+	}
+
+	updateUI := func() {
+		w, h := ui.TerminalDimensions()
+		cmp.page.Grid.SetRect(0, 0, w, h)
+
+		ui.Clear()
+		switch utilitySelected {
+		case core.Help:
+			help.Resize(w, h)
+			ui.Render(help)
+
+		default:
+			ui.Render(cmp.page.Grid)
+		}
+	}
+
+	uiEvents := ui.PollEvents()
+	t := time.NewTicker(time.Duration(refreshRate) * time.Millisecond)
+	tick := t.C
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case e := <-uiEvents:
+			switch e.ID {
+			case "q", "<C-c>": //q or Ctrl-C to quit
+				return core.ErrCanceledByUser
+
+			case "<Resize>":
+				updateUI()
+
+			case "?":
+				utilitySelected = core.Help
+				updateUI()
+
+			case "<Escape>":
+				utilitySelected = core.None
+				updateUI()
+
+			case "p":
+				pause()
+				if runProc {
+					cmp.draw()
+				}
+			}
+
+		case data := <-dataChannel:
+			// the history is recorded while paused, only the page is frozen.
+			cmp.record(data, time.Now())
+			if runProc {
+				cmp.draw()
+				on.Do(updateUI)
+			}
+
+		case <-tick:
+			if utilitySelected == core.None {
+				ui.Render(cmp.page.Grid)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+	viz "github.com/pesos/grofer/pkg/utils/visualization"
+	proc "github.com/shirou/gopsutil/process"
+)
+
+// newTestComparison returns a comparison whose page is not laid out, which
+// needs a terminal.
+func newTestComparison() *comparison {
+	return &comparison{
+		page: &comparePage{
+			Table:            widgets.NewTable(),
+			CPUGraph:         viz.NewLineGraph(),
+			RSSGraph:         viz.NewLineGraph(),
+			ThreadsGraph:     viz.NewLineGraph(),
+			CTXSwitchesGraph: viz.NewLineGraph(),
+			PageFaultsGraph:  viz.NewLineGraph(),
+		},
+		histories: make(map[int32]*procHistory),
+	}
+}
+
+func TestGetCompareRows(t *testing.T) {
+	procs := []*process.Process{
+		{
+			Proc:           &proc.Process{Pid: 10},
+			Name:           "postgres",
+			Status:         "S",
+			IsRunning:      true,
+			CPUPercent:     12.5,
+			MemoryPercent:  1.25,
+			NumThreads:     4,
+			MemoryInfo:     &proc.MemoryInfoStat{RSS: 2 << 20},
+			NumCtxSwitches: &proc.NumCtxSwitchesStat{Voluntary: 100, Involuntary: 10},
+			PageFault:      &proc.PageFaultsStat{MinorFaults: 1000, MajorFaults: 2},
+			IO:             &process.IOStat{ReadBytes: 8192},
+			IORate:         process.IORate{ReadBytes: 2048},
+		},
+		{
+			Proc:   &proc.Process{Pid: 20},
+			Name:   "postgres",
+			Status: "Z",
+		},
+	}
+
+	cmp := newTestComparison()
+	now := time.Unix(1000, 0)
+	cmp.record(procs, now)
+	procs[0].NumCtxSwitches = &proc.NumCtxSwitchesStat{Voluntary: 130, Involuntary: 10}
+	cmp.record(procs, now.Add(2*time.Second))
+
+	rows := getCompareRows(procs, cmp.histories)
+	utils.Equals(t, 14, len(rows))
+	utils.Equals(t, []string{"PID", "[10](fg:green,mod:bold)", "[20](fg:magenta,mod:bold)"}, rows[0])
+	utils.Equals(t, []string{"Status", "Sleep (S)", "Exited"}, rows[2])
+	utils.Equals(t, []string{"CPU %", "12.50", "0.00"}, rows[3])
+	utils.Equals(t, []string{"RSS", "2.0M", "-"}, rows[5])
+	utils.Equals(t, []string{"Ctx switches (vol/inv)", "130 / 10", "-"}, rows[7])
+	utils.Equals(t, []string{"Ctx switches/s", "15.0", "-"}, rows[8])
+	utils.Equals(t, []string{"Page faults/s", "0.0", "-"}, rows[10])
+	utils.Equals(t, []string{"Disk read/s", "2.0K/s", "-"}, rows[11])
+	utils.Equals(t, []string{"Disk write/s", "0B/s", "-"}, rows[12])
+}
+
+func TestComparisonSharesGraphs(t *testing.T) {
+	procs := []*process.Process{
+		{Proc: &proc.Process{Pid: 10}, CPUPercent: 5},
+		{Proc: &proc.Process{Pid: 20}, CPUPercent: 7},
+	}
+
+	cmp := newTestComparison()
+	cmp.record(procs, time.Unix(1000, 0))
+	cmp.draw()
+
+	graph := cmp.page.CPUGraph
+	utils.Equals(t, []float64{5}, graph.Data["10"])
+	utils.Equals(t, []float64{7}, graph.Data["20"])
+	utils.Equals(t, compareColors[0], graph.LineColors["10"])
+	utils.Equals(t, compareColors[1], graph.LineColors["20"])
+	utils.Equals(t, "7.00%", graph.Labels["20"])
+
+	// samples recorded while paused are drawn once resumed.
+	procs[1].CPUPercent = 9
+	cmp.record(procs, time.Unix(1001, 0))
+	utils.Equals(t, []float64{7}, graph.Data["20"])
+	cmp.draw()
+	utils.Equals(t, []float64{7, 9}, graph.Data["20"])
+	utils.Equals(t, "9.00%", graph.Labels["20"])
+}
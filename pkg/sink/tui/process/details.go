@@ -0,0 +1,165 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+// detailsTab describes a tab of the details of a process, the last column
+// takes the space left by the others.
+type detailsTab struct {
+	title  string
+	header []string
+	widths []int
+	rows   func(p *process.Process) ([][]string, error)
+}
+
+// detailsTabs are the tabs of the details of a process, in order.
+var detailsTabs = []detailsTab{
+	{
+		title:  "Arguments",
+		header: []string{"#", "Argument"},
+		widths: []int{5, 20},
+		rows: func(p *process.Process) ([][]string, error) {
+			rows := make([][]string, 0, len(p.Details.Argv))
+			for i, arg := range p.Details.Argv {
+				rows = append(rows, []string{strconv.Itoa(i), arg})
+			}
+			return rows, p.Details.ArgvErr
+		},
+	},
+	{
+		title:  "Environment",
+		header: []string{"Variable", "Value"},
+		widths: []int{30, 20},
+		rows: func(p *process.Process) ([][]string, error) {
+			rows := make([][]string, 0, len(p.Details.Environ))
+			for _, env := range p.Details.Environ {
+				kv := strings.SplitN(env, "=", 2)
+				if len(kv) == 1 {
+					kv = append(kv, "")
+				}
+				rows = append(rows, kv)
+			}
+			return rows, p.Details.EnvironErr
+		},
+	},
+	{
+		title:  "Limits",
+		header: []string{"Limit", "Soft", "Hard", "Units"},
+		widths: []int{26, 16, 16, 10},
+		rows: func(p *process.Process) ([][]string, error) {
+			rows := make([][]string, 0, len(p.Details.Limits))
+			for _, l := range p.Details.Limits {
+				rows = append(rows, []string{l.Name, l.Soft, l.Hard, l.Units})
+			}
+			return rows, p.Details.LimitsErr
+		},
+	},
+	{
+		title:  "Cgroups",
+		header: []string{"Hierarchy", "Controllers", "Path"},
+		widths: []int{10, 20, 20},
+		rows: func(p *process.Process) ([][]string, error) {
+			rows := make([][]string, 0, len(p.Details.Cgroups))
+			for _, c := range p.Details.Cgroups {
+				controllers := c.Controllers
+				if c.Hierarchy == "0" && controllers == "" {
+					controllers = "(unified)"
+				}
+				rows = append(rows, []string{c.Hierarchy, controllers, c.Path})
+			}
+			return rows, p.Details.CgroupsErr
+		},
+	},
+	{
+		title:  "Namespaces",
+		header: []string{"Type", "ID", "Shared with grofer"},
+		widths: []int{20, 14, 10},
+		rows: func(p *process.Process) ([][]string, error) {
+			rows := make([][]string, 0, len(p.Details.Namespaces))
+			for _, ns := range p.Details.Namespaces {
+				rows = append(rows, []string{ns.Type, ns.ID, strconv.FormatBool(ns.Shared)})
+			}
+			return rows, p.Details.NamespacesErr
+		},
+	},
+	{
+		title:  "Scheduling",
+		header: []string{"Setting", "Value"},
+		widths: []int{20, 20},
+		rows: func(p *process.Process) ([][]string, error) {
+			s := p.Details.Scheduling
+			if p.Details.SchedulingErr != nil {
+				return nil, p.Details.SchedulingErr
+			}
+
+			ioPriority := s.IOPriority
+			if ioPriority == "" {
+				ioPriority = "NA"
+			}
+			lastCPU := "NA"
+			if s.LastCPU >= 0 {
+				lastCPU = strconv.Itoa(int(s.LastCPU))
+			}
+			affinity := "NA"
+			if len(s.CPUAffinity) > 0 {
+				affinity = process.FormatCPUList(s.CPUAffinity)
+			}
+			return [][]string{
+				{"Policy", s.Policy},
+				{"Realtime priority", strconv.Itoa(int(s.RTPriority))},
+				{"Nice", strconv.Itoa(int(s.Nice))},
+				{"I/O priority", ioPriority},
+				{"CPU affinity", affinity},
+				{"Last CPU", lastCPU},
+			}, nil
+		},
+	},
+}
+
+// detailsTabNames returns the titles of the tabs of the details of a
+// process.
+func detailsTabNames() []string {
+	names := make([]string, len(detailsTabs))
+	for i, tab := range detailsTabs {
+		names[i] = tab.title
+	}
+	return names
+}
+
+// getDetailsRows returns the rows and the title of the table of a tab of
+// the details of a process. A section that cannot be read is explained in
+// the title.
+func getDetailsRows(tab detailsTab, p *process.Process) ([][]string, string) {
+	rows, err := tab.rows(p)
+	switch {
+	case err == nil:
+		return rows, fmt.Sprintf(" %s ", tab.title)
+	case errors.Is(err, os.ErrPermission):
+		return nil, fmt.Sprintf(" %s: permission denied, run grofer as the owner of the process or as root ", tab.title)
+	default:
+		return nil, fmt.Sprintf(" %s: %v ", tab.title, err)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGetDetailsRows(t *testing.T) {
+	p := &process.Process{
+		Details: process.Details{
+			Environ:    []string{"EMPTY=", "PATH=/usr/bin:/bin", "TOKEN=a=b"},
+			LimitsErr:  fmt.Errorf("open /proc/1/limits: %w", os.ErrPermission),
+			Scheduling: process.Scheduling{Policy: "SCHED_OTHER", Nice: -5, LastCPU: -1, CPUAffinity: []int{0, 1, 2, 5}},
+		},
+	}
+
+	rows, title := getDetailsRows(detailsTabs[1], p)
+	utils.Equals(t, " Environment ", title)
+	utils.Equals(t, [][]string{{"EMPTY", ""}, {"PATH", "/usr/bin:/bin"}, {"TOKEN", "a=b"}}, rows)
+
+	// sections of processes of other users explain why they are empty.
+	rows, title = getDetailsRows(detailsTabs[2], p)
+	utils.Equals(t, 0, len(rows))
+	utils.Equals(t, " Limits: permission denied, run grofer as the owner of the process or as root ", title)
+
+	rows, _ = getDetailsRows(detailsTabs[5], p)
+	utils.Equals(t, []string{"Nice", "-5"}, rows[2])
+	utils.Equals(t, []string{"I/O priority", "NA"}, rows[3])
+	utils.Equals(t, []string{"CPU affinity", "0-2,5"}, rows[4])
+	utils.Equals(t, []string{"Last CPU", "NA"}, rows[5])
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+// eventsHeader is the header of the process events table, the command
+// column takes the space left by the others.
+var eventsHeader = []string{"Time", "Event", "PID", "PPID", "Command", "Lifetime", "CPU Time", "Peak RSS", "Exit Status"}
+
+// eventsColWidths are the minimum widths of the columns of the process
+// events table.
+var eventsColWidths = []int{10, 7, 8, 8, 20, 12, 10, 10, 16}
+
+// getEventRows returns the rows of the process events table, newest first.
+func getEventRows(events []process.Event) [][]string {
+	rows := make([][]string, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		command := e.Process.Cmdline
+		if command == "" {
+			command = "[" + e.Process.Name + "]"
+		}
+		rows = append(rows, []string{
+			e.Time.Format("15:04:05"),
+			e.Kind.String(),
+			fmt.Sprintf("%d", e.Process.PID),
+			fmt.Sprintf("%d", e.Process.PPID),
+			command,
+			formatDuration(e.Lifetime),
+			formatDuration(e.Process.CPUTime),
+			formatBytes(e.PeakRSS),
+			e.ExitStatus,
+		})
+	}
+	return rows
+}
+
+// getEventsTitle returns the title of the process events table.
+func getEventsTitle(events []process.Event) string {
+	started, exited := 0, 0
+	for _, e := range events {
+		switch e.Kind {
+		case process.ProcessStarted:
+			started++
+		case process.ProcessExited:
+			exited++
+		}
+	}
+	return fmt.Sprintf(" Process Events: %d started, %d exited ", started, exited)
+}
+
+// formatDuration formats a duration in seconds if it is under a minute,
+// as processes of interest in the event log are often short lived.
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return utils.SecondsToHuman(int(d.Seconds()))
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGetEventRows(t *testing.T) {
+	at := time.Date(2021, 9, 1, 14, 30, 5, 0, time.Local)
+	events := []process.Event{
+		{
+			Time:     at,
+			Kind:     process.ProcessStarted,
+			Process:  process.Snapshot{PID: 7, PPID: 1, Name: "kworker/0:1"},
+			Lifetime: 250 * time.Millisecond,
+		},
+		{
+			Time:       at.Add(time.Second),
+			Kind:       process.ProcessExited,
+			Process:    process.Snapshot{PID: 8, PPID: 7, Name: "sh", Cmdline: "sh -c make", CPUTime: 1500 * time.Millisecond},
+			Lifetime:   90 * time.Second,
+			PeakRSS:    3 << 20,
+			ExitStatus: "exited 2",
+		},
+	}
+
+	rows := getEventRows(events)
+	utils.Equals(t, 2, len(rows))
+	utils.Equals(t, []string{"14:30:06", "exit", "8", "7", "sh -c make", "1M 30S", "1.50s", "3.0M", "exited 2"}, rows[0])
+	// kernel threads have no command line.
+	utils.Equals(t, "[kworker/0:1]", rows[1][4])
+	utils.Equals(t, "0.25s", rows[1][5])
+
+	utils.Equals(t, " Process Events: 1 started, 1 exited ", getEventsTitle(events))
+}
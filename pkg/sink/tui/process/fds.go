@@ -0,0 +1,59 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+// getFDRows returns the rows of the open files table. Known sockets show
+// their protocol and addresses in place of the socket inode.
+func getFDRows(fds []process.FileDescriptor) [][]string {
+	rows := make([][]string, 0, len(fds))
+	for _, fd := range fds {
+		target := fd.Target
+		switch {
+		case fd.Protocol == "unix" && fd.LocalAddress != "":
+			target = "unix " + fd.LocalAddress
+		case fd.Protocol == "unix":
+			target = "unix " + fd.Target
+		case fd.Protocol != "" && fd.RemoteAddress != "":
+			target = fmt.Sprintf("%s %s -> %s", fd.Protocol, fd.LocalAddress, fd.RemoteAddress)
+		case fd.Protocol != "":
+			target = fd.Protocol + " " + fd.LocalAddress
+		}
+		rows = append(rows, []string{strconv.Itoa(fd.FD), fd.Type, target, fd.State})
+	}
+	return rows
+}
+
+// unreadFDsTitle is the title of the open files table while it is not
+// focused and the open files are not read.
+const unreadFDsTitle = " Open Files (<Tab> to read) "
+
+// getFDTitle returns the title of the open files table, showing the number
+// of open descriptors against the soft limit of the process.
+func getFDTitle(count int, limit uint64) string {
+	max := "unlimited"
+	if limit != 0 {
+		max = strconv.FormatUint(limit, 10)
+	}
+	return fmt.Sprintf(" Open Files: %d of %s ", count, max)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGetFDRows(t *testing.T) {
+	fds := []process.FileDescriptor{
+		{FD: 0, Type: "file", Target: "/dev/null"},
+		{FD: 3, Type: "socket", Target: "socket:[200]", Protocol: "tcp",
+			LocalAddress: "127.0.0.1:8080", RemoteAddress: "10.0.0.2:443", State: "ESTABLISHED"},
+		{FD: 4, Type: "socket", Target: "socket:[201]", Protocol: "tcp6",
+			LocalAddress: "[::]:22", RemoteAddress: "", State: "LISTEN"},
+		{FD: 5, Type: "socket", Target: "socket:[300]", Protocol: "unix",
+			LocalAddress: "/run/grofer.sock", State: "CONNECTED"},
+		{FD: 6, Type: "socket", Target: "socket:[400]"},
+	}
+
+	utils.Equals(t, [][]string{
+		{"0", "file", "/dev/null", ""},
+		{"3", "socket", "tcp 127.0.0.1:8080 -> 10.0.0.2:443", "ESTABLISHED"},
+		{"4", "socket", "tcp6 [::]:22", "LISTEN"},
+		{"5", "socket", "unix /run/grofer.sock", "CONNECTED"},
+		{"6", "socket", "socket:[400]", ""},
+	}, getFDRows(fds))
+}
+
+func TestGetFDTitle(t *testing.T) {
+	utils.Equals(t, " Open Files: 12 of 1024 ", getFDTitle(12, 1024))
+	utils.Equals(t, " Open Files: 3 of unlimited ", getFDTitle(3, 0))
+}
@@ -0,0 +1,242 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+// grouping describes a way of collapsing processes into groups.
+type grouping struct {
+	name string
+	key  func(p process.Snapshot) string
+}
+
+// groupings are the ways the process table can be grouped, in the order
+// they are cycled through.
+var groupings = []grouping{
+	{
+		name: "command",
+		key:  func(p process.Snapshot) string { return p.Name },
+	},
+	{
+		name: "user",
+		key: func(p process.Snapshot) string {
+			if p.Username != "" {
+				return p.Username
+			}
+			return fmt.Sprintf("%d", p.UID)
+		},
+	},
+	{
+		name: "cgroup",
+		key: func(p process.Snapshot) string {
+			if p.Cgroup != "" {
+				return p.Cgroup
+			}
+			return "-"
+		},
+	},
+}
+
+// procGroup holds the totals of the processes sharing a group key. The
+// I/O totals and rates only cover the ioCount processes whose counters are
+// readable.
+type procGroup struct {
+	key     string
+	count   int
+	ioCount int
+	total   process.Snapshot
+}
+
+// groupProcs collapses processes into one group per key, in the order the
+// keys are first seen.
+func groupProcs(procs []process.Snapshot, by grouping) []procGroup {
+	index := make(map[string]int)
+	groups := []procGroup{}
+	for _, p := range procs {
+		key := by.key(p)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, procGroup{key: key})
+		}
+		g := &groups[i]
+		g.count++
+		g.total.CPUPercent += p.CPUPercent
+		g.total.MemoryPercent += p.MemoryPercent
+		g.total.RSS += p.RSS
+		g.total.NumThreads += p.NumThreads
+		if p.IOReadable {
+			g.ioCount++
+			g.total.IO.ReadBytes += p.IO.ReadBytes
+			g.total.IO.WriteBytes += p.IO.WriteBytes
+			g.total.IORate.ReadBytes += p.IORate.ReadBytes
+			g.total.IORate.WriteBytes += p.IORate.WriteBytes
+		}
+	}
+	return groups
+}
+
+// groupMembers returns the processes belonging to the group with the
+// given key.
+func groupMembers(procs []process.Snapshot, by grouping, key string) []process.Snapshot {
+	members := []process.Snapshot{}
+	for _, p := range procs {
+		if by.key(p) == key {
+			members = append(members, p)
+		}
+	}
+	return members
+}
+
+// formatGroupIO formats an I/O total or rate of a group, "-" if the
+// counters of none of its processes are readable rather than zero. Totals
+// that leave out some of the processes are marked with a "*".
+func formatGroupIO(g procGroup, value string) string {
+	switch {
+	case g.ioCount == 0:
+		return "-"
+	case g.ioCount < g.count:
+		return value + "*"
+	default:
+		return value
+	}
+}
+
+// groupColumn describes a column of the grouped process table.
+type groupColumn struct {
+	title string
+	width int
+	value func(g procGroup) string
+	less  func(a, b procGroup) bool
+	// unset reports whether the value of a group is not known, if set.
+	// Those groups sort below the others in either direction.
+	unset func(g procGroup) bool
+}
+
+// unreadableGroupIO reports whether the I/O counters of none of the
+// processes of a group are readable.
+func unreadableGroupIO(g procGroup) bool {
+	return g.ioCount == 0
+}
+
+// groupColumns are the columns of the grouped process table, the group
+// column takes the space left by the others.
+var groupColumns = []groupColumn{
+	{
+		title: "Group",
+		width: 30,
+		value: func(g procGroup) string { return g.key },
+		less:  func(a, b procGroup) bool { return a.key < b.key },
+	},
+	{
+		title: "Procs",
+		width: 8,
+		value: func(g procGroup) string { return fmt.Sprintf("%d", g.count) },
+		less:  func(a, b procGroup) bool { return a.count < b.count },
+	},
+	{
+		title: "CPU",
+		width: 10,
+		value: func(g procGroup) string { return fmt.Sprintf("%.2f%%", g.total.CPUPercent) },
+		less:  func(a, b procGroup) bool { return a.total.CPUPercent < b.total.CPUPercent },
+	},
+	{
+		title: "Memory",
+		width: 10,
+		value: func(g procGroup) string { return fmt.Sprintf("%.2f%%", g.total.MemoryPercent) },
+		less:  func(a, b procGroup) bool { return a.total.MemoryPercent < b.total.MemoryPercent },
+	},
+	{
+		title: "RSS",
+		width: 10,
+		value: func(g procGroup) string { return formatBytes(g.total.RSS) },
+		less:  func(a, b procGroup) bool { return a.total.RSS < b.total.RSS },
+	},
+	{
+		title: "Threads",
+		width: 9,
+		value: func(g procGroup) string { return fmt.Sprintf("%d", g.total.NumThreads) },
+		less:  func(a, b procGroup) bool { return a.total.NumThreads < b.total.NumThreads },
+	},
+	{
+		title: "Read",
+		width: 10,
+		value: func(g procGroup) string { return formatGroupIO(g, formatBytes(g.total.IO.ReadBytes)) },
+		less:  func(a, b procGroup) bool { return a.total.IO.ReadBytes < b.total.IO.ReadBytes },
+		unset: unreadableGroupIO,
+	},
+	{
+		title: "Write",
+		width: 10,
+		value: func(g procGroup) string { return formatGroupIO(g, formatBytes(g.total.IO.WriteBytes)) },
+		less:  func(a, b procGroup) bool { return a.total.IO.WriteBytes < b.total.IO.WriteBytes },
+		unset: unreadableGroupIO,
+	},
+	{
+		title: "Read/s",
+		width: 11,
+		value: func(g procGroup) string { return formatGroupIO(g, formatByteRate(g.total.IORate.ReadBytes)) },
+		less:  func(a, b procGroup) bool { return a.total.IORate.ReadBytes < b.total.IORate.ReadBytes },
+		unset: unreadableGroupIO,
+	},
+	{
+		title: "Write/s",
+		width: 11,
+		value: func(g procGroup) string { return formatGroupIO(g, formatByteRate(g.total.IORate.WriteBytes)) },
+		less:  func(a, b procGroup) bool { return a.total.IORate.WriteBytes < b.total.IORate.WriteBytes },
+		unset: unreadableGroupIO,
+	},
+}
+
+// getGroupRows returns the rows of the grouped process table and the key of
+// the group on each row, ordered by the sort column if sortIdx is not -1.
+// The groups whose value is not known are placed last.
+func getGroupRows(groups []procGroup, sortIdx int, sortAsc bool) ([][]string, []string) {
+	if sortIdx != -1 {
+		groups = append([]procGroup{}, groups...)
+		less, unset := groupColumns[sortIdx].less, groupColumns[sortIdx].unset
+		sort.SliceStable(groups, func(i, j int) bool {
+			if unset != nil {
+				if unsetI, unsetJ := unset(groups[i]), unset(groups[j]); unsetI != unsetJ {
+					return unsetJ
+				}
+			}
+			if sortAsc {
+				return less(groups[i], groups[j])
+			}
+			return less(groups[j], groups[i])
+		})
+	}
+
+	rows := make([][]string, 0, len(groups))
+	keys := make([]string, 0, len(groups))
+	for _, g := range groups {
+		row := make([]string, len(groupColumns))
+		for i, col := range groupColumns {
+			row[i] = col.value(g)
+		}
+		rows = append(rows, row)
+		keys = append(keys, g.key)
+	}
+	return rows, keys
+}
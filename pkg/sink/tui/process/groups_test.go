@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGroupProcs(t *testing.T) {
+	procs := []process.Snapshot{
+		{PID: 1, Name: "chrome", Username: "alice", CPUPercent: 10, RSS: 1 << 20, NumThreads: 4, IO: process.IOStat{ReadBytes: 100}, IORate: process.IORate{ReadBytes: 1.5}, IOReadable: true},
+		{PID: 2, Name: "bash", UID: 1001, CPUPercent: 1, RSS: 2 << 20, NumThreads: 1},
+		{PID: 3, Name: "chrome", Username: "alice", CPUPercent: 30, RSS: 3 << 20, NumThreads: 6, Cgroup: "/user.slice", IO: process.IOStat{ReadBytes: 50}},
+	}
+
+	groups := groupProcs(procs, groupings[0])
+	utils.Equals(t, 2, len(groups))
+	utils.Equals(t, "chrome", groups[0].key)
+	utils.Equals(t, 2, groups[0].count)
+	utils.Equals(t, 40.0, groups[0].total.CPUPercent)
+	utils.Equals(t, uint64(4<<20), groups[0].total.RSS)
+	utils.Equals(t, int32(10), groups[0].total.NumThreads)
+
+	// the I/O counters of a process that are not readable are left out.
+	utils.Equals(t, 1, groups[0].ioCount)
+	utils.Equals(t, uint64(100), groups[0].total.IO.ReadBytes)
+	utils.Equals(t, 1.5, groups[0].total.IORate.ReadBytes)
+	utils.Equals(t, 0, groups[1].ioCount)
+
+	// users without a name are grouped by UID.
+	groups = groupProcs(procs, groupings[1])
+	utils.Equals(t, "1001", groups[1].key)
+
+	// processes whose cgroup is unknown share a group.
+	groups = groupProcs(procs, groupings[2])
+	utils.Equals(t, []string{"-", "/user.slice"}, []string{groups[0].key, groups[1].key})
+	utils.Equals(t, 2, groups[0].count)
+
+	members := groupMembers(procs, groupings[0], "chrome")
+	utils.Equals(t, []int32{1, 3}, []int32{members[0].PID, members[1].PID})
+}
+
+func TestGetGroupRows(t *testing.T) {
+	groups := []procGroup{
+		{key: "bash", count: 3, total: process.Snapshot{CPUPercent: 2}},
+		{key: "chrome", count: 12, ioCount: 12, total: process.Snapshot{CPUPercent: 40, RSS: 3 << 30}},
+		{key: "sshd", count: 3, ioCount: 1, total: process.Snapshot{IORate: process.IORate{WriteBytes: 2048}}},
+	}
+
+	rows, keys := getGroupRows(groups, -1, false)
+	utils.Equals(t, []string{"chrome", "12", "40.00%", "0.00%", "3.0G", "0", "0B", "0B", "0B/s", "0B/s"}, rows[1])
+	utils.Equals(t, []string{"bash", "chrome", "sshd"}, keys)
+
+	// groups without readable I/O counters show no total and sort last,
+	// partial totals are marked.
+	utils.Equals(t, []string{"-", "-", "-", "-"}, rows[0][6:])
+	utils.Equals(t, []string{"0B*", "0B*", "0B/s*", "2.0K/s*"}, rows[2][6:])
+	_, keys = getGroupRows(groups, 9, false)
+	utils.Equals(t, []string{"sshd", "chrome", "bash"}, keys)
+	_, keys = getGroupRows(groups, 6, false)
+	utils.Equals(t, []string{"chrome", "sshd", "bash"}, keys)
+	_, keys = getGroupRows(groups, 6, true)
+	utils.Equals(t, []string{"chrome", "sshd", "bash"}, keys)
+
+	// counts sort numerically, the largest group first.
+	_, keys = getGroupRows(groups, 1, false)
+	utils.Equals(t, []string{"chrome", "bash", "sshd"}, keys)
+}
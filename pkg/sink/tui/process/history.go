@@ -0,0 +1,156 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+// maxHistory is the number of samples kept for every history graph, which
+// is more than the width of a terminal can show.
+const maxHistory = 1024
+
+// series is a rolling window of samples along with the peak of every
+// sample added over the session, and the samples at which the process
+// was restarted.
+type series struct {
+	data    []float64
+	peak    float64
+	added   int   // number of samples added over the session.
+	markers []int // indices of the first sample of every restart.
+}
+
+func (s *series) add(v float64) {
+	s.added++
+	s.data = append(s.data, v)
+	if len(s.data) > maxHistory {
+		s.data = s.data[len(s.data)-maxHistory:]
+	}
+	if v > s.peak {
+		s.peak = v
+	}
+}
+
+// last returns the latest sample or 0 if there is none.
+func (s *series) last() float64 {
+	if len(s.data) == 0 {
+		return 0
+	}
+	return s.data[len(s.data)-1]
+}
+
+// mark marks the next sample added as the first one of a restart.
+func (s *series) mark() {
+	s.markers = append(s.markers, s.added)
+}
+
+// markersAgo returns how many samples ago each restart in the window
+// happened, 0 being the latest sample.
+func (s *series) markersAgo() []int {
+	var ago []int
+	kept := s.markers[:0]
+	for _, m := range s.markers {
+		n := s.added - 1 - m
+		if n >= maxHistory {
+			continue
+		}
+		kept = append(kept, m)
+		if n >= 0 {
+			ago = append(ago, n)
+		}
+	}
+	s.markers = kept
+	return ago
+}
+
+// counter turns the samples of a cumulative counter into a rate per second.
+type counter struct {
+	prev     uint64
+	prevTime time.Time
+}
+
+// rate returns the rate since the previous sample, and false for the first
+// sample or if the counter went back.
+func (c *counter) rate(v uint64, now time.Time) (float64, bool) {
+	prev, prevTime := c.prev, c.prevTime
+	c.prev, c.prevTime = v, now
+	if prevTime.IsZero() || v < prev {
+		return 0, false
+	}
+	elapsed := now.Sub(prevTime).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return float64(v-prev) / elapsed, true
+}
+
+// procHistory keeps the history of the metrics graphed for a process.
+type procHistory struct {
+	cpu              series
+	rss              series
+	threads          series
+	ctxSwitches      series // per second.
+	pageFaults       series // per second.
+	diskRead         series // bytes per second.
+	diskWrite        series // bytes per second.
+	ctxSwitchCounter counter
+	pageFaultCounter counter
+	diskReadCounter  counter
+	diskWriteCounter counter
+}
+
+// add records a sample of the process taken at the given time.
+func (h *procHistory) add(p *process.Process, now time.Time) {
+	h.cpu.add(p.CPUPercent)
+	h.threads.add(float64(p.NumThreads))
+	if p.MemoryInfo != nil {
+		h.rss.add(float64(p.MemoryInfo.RSS))
+	}
+
+	if p.NumCtxSwitches != nil {
+		switches := p.NumCtxSwitches.Voluntary + p.NumCtxSwitches.Involuntary
+		if rate, ok := h.ctxSwitchCounter.rate(uint64(switches), now); ok {
+			h.ctxSwitches.add(rate)
+		}
+	}
+	if p.PageFault != nil {
+		faults := p.PageFault.MinorFaults + p.PageFault.MajorFaults
+		if rate, ok := h.pageFaultCounter.rate(faults, now); ok {
+			h.pageFaults.add(rate)
+		}
+	}
+	if p.IO != nil {
+		if rate, ok := h.diskReadCounter.rate(p.IO.ReadBytes, now); ok {
+			h.diskRead.add(rate)
+		}
+		if rate, ok := h.diskWriteCounter.rate(p.IO.WriteBytes, now); ok {
+			h.diskWrite.add(rate)
+		}
+	}
+}
+
+// restart marks every graph as another process being followed from the
+// next sample on.
+func (h *procHistory) restart() {
+	for _, s := range []*series{&h.cpu, &h.rss, &h.threads, &h.ctxSwitches, &h.pageFaults, &h.diskRead, &h.diskWrite} {
+		s.mark()
+	}
+	h.ctxSwitchCounter, h.pageFaultCounter = counter{}, counter{}
+	h.diskReadCounter, h.diskWriteCounter = counter{}, counter{}
+}
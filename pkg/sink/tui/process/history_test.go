@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+	proc "github.com/shirou/gopsutil/process"
+)
+
+func TestSeriesKeepsWindowAndPeak(t *testing.T) {
+	var s series
+	s.add(5)
+	for i := 0; i < maxHistory; i++ {
+		s.add(1)
+	}
+
+	utils.Equals(t, maxHistory, len(s.data))
+	utils.Equals(t, 1.0, s.last())
+	// the peak outlives the window.
+	utils.Equals(t, 5.0, s.peak)
+}
+
+func TestCounterRate(t *testing.T) {
+	var c counter
+	now := time.Unix(1000, 0)
+
+	_, ok := c.rate(100, now)
+	utils.Assert(t, !ok, "the first sample should have no rate")
+
+	rate, ok := c.rate(160, now.Add(3*time.Second))
+	utils.Assert(t, ok, "the second sample should have a rate")
+	utils.Equals(t, 20.0, rate)
+
+	// a counter going back, ex - a reused PID, starts over.
+	_, ok = c.rate(10, now.Add(4*time.Second))
+	utils.Assert(t, !ok, "a counter going back should have no rate")
+	rate, _ = c.rate(12, now.Add(5*time.Second))
+	utils.Equals(t, 2.0, rate)
+}
+
+func TestProcHistoryContextSwitchRate(t *testing.T) {
+	var h procHistory
+	now := time.Unix(1000, 0)
+	p := &process.Process{
+		CPUPercent:     12.5,
+		NumThreads:     4,
+		MemoryInfo:     &proc.MemoryInfoStat{RSS: 2048},
+		NumCtxSwitches: &proc.NumCtxSwitchesStat{Voluntary: 100, Involuntary: 10},
+		PageFault:      &proc.PageFaultsStat{MinorFaults: 1000, MajorFaults: 4},
+		IO:             &process.IOStat{ReadBytes: 4096, WriteBytes: 0},
+	}
+
+	// a single sample has no rate yet.
+	h.add(p, now)
+	utils.Equals(t, 0, len(h.ctxSwitches.data))
+	utils.Equals(t, 0, len(h.diskRead.data))
+	utils.Equals(t, 12.5, h.cpu.last())
+	utils.Equals(t, 2048.0, h.rss.last())
+	utils.Equals(t, 4.0, h.threads.last())
+
+	p.NumCtxSwitches = &proc.NumCtxSwitchesStat{Voluntary: 150, Involuntary: 20}
+	p.PageFault = &proc.PageFaultsStat{MinorFaults: 1100, MajorFaults: 6}
+	p.IO = &process.IOStat{ReadBytes: 12288, WriteBytes: 1024}
+	h.add(p, now.Add(2*time.Second))
+	utils.Equals(t, 30.0, h.ctxSwitches.last())
+	utils.Equals(t, 51.0, h.pageFaults.last())
+	utils.Equals(t, 4096.0, h.diskRead.last())
+	utils.Equals(t, 512.0, h.diskWrite.last())
+}
+
+func TestProcHistoryRestartMarkers(t *testing.T) {
+	var h procHistory
+	now := time.Unix(1000, 0)
+	p := &process.Process{
+		MemoryInfo:     &proc.MemoryInfoStat{RSS: 2048},
+		NumCtxSwitches: &proc.NumCtxSwitchesStat{Voluntary: 100},
+	}
+	h.add(p, now)
+	h.add(p, now.Add(time.Second))
+
+	// no marker is shown until a sample of the new process is added.
+	h.restart()
+	utils.Equals(t, 0, len(h.cpu.markersAgo()))
+
+	// the counters of the new process start over without a rate.
+	p.NumCtxSwitches = &proc.NumCtxSwitchesStat{Voluntary: 5}
+	h.add(p, now.Add(2*time.Second))
+	utils.Equals(t, []int{0}, h.cpu.markersAgo())
+	utils.Equals(t, 1, len(h.ctxSwitches.data))
+
+	h.add(p, now.Add(3*time.Second))
+	utils.Equals(t, []int{1}, h.rss.markersAgo())
+	utils.Equals(t, []int{0}, h.ctxSwitches.markersAgo())
+
+	// markers are dropped once they leave the window.
+	for i := 0; i < maxHistory; i++ {
+		h.cpu.add(0)
+	}
+	utils.Equals(t, 0, len(h.cpu.markersAgo()))
+	utils.Equals(t, 0, len(h.cpu.markers))
+}
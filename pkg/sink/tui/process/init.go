@@ -29,9 +29,18 @@ type perProcPage struct {
 	MemChart         *widgets.Gauge
 	PIDTable         *widgets.Table
 	ChildProcsTable  *viz.Table
+	ThreadsTable     *viz.Table
+	FDsTable         *viz.Table
 	CTXSwitchesChart *viz.BarChart
 	PageFaultsChart  *viz.BarChart
 	MemStatsChart    *viz.BarChart
+	CPUGraph         *viz.LineGraph
+	RSSGraph         *viz.LineGraph
+	ThreadsGraph     *viz.LineGraph
+	CTXSwitchesGraph *viz.LineGraph
+	DiskIOGraph      *viz.LineGraph
+	DetailsTabs      *widgets.TabPane
+	DetailsTable     *viz.Table
 }
 
 // newPerProcPage initializes a new page from the perProcPage struct and returns it
@@ -42,9 +51,18 @@ func newPerProcPage() *perProcPage {
 		MemChart:         widgets.NewGauge(),
 		PIDTable:         widgets.NewTable(),
 		ChildProcsTable:  viz.NewTable(),
+		ThreadsTable:     viz.NewTable(),
+		FDsTable:         viz.NewTable(),
 		CTXSwitchesChart: viz.NewBarChart(),
 		PageFaultsChart:  viz.NewBarChart(),
 		MemStatsChart:    viz.NewBarChart(),
+		CPUGraph:         viz.NewLineGraph(),
+		RSSGraph:         viz.NewLineGraph(),
+		ThreadsGraph:     viz.NewLineGraph(),
+		CTXSwitchesGraph: viz.NewLineGraph(),
+		DiskIOGraph:      viz.NewLineGraph(),
+		DetailsTabs:      widgets.NewTabPane(detailsTabNames()...),
+		DetailsTable:     viz.NewTable(),
 	}
 	page.init()
 	return page
@@ -90,6 +108,41 @@ func (page *perProcPage) init() {
 		}
 	}
 
+	// Initialize Table for Threads Table
+	page.ThreadsTable.Title = " Threads "
+	page.ThreadsTable.BorderStyle.Fg = ui.ColorCyan
+	page.ThreadsTable.TitleStyle.Fg = ui.ColorClear
+	page.ThreadsTable.Header = make([]string, len(threadColumns))
+	page.ThreadsTable.ColWidths = make([]int, len(threadColumns))
+	fixedWidth := 0
+	for i, col := range threadColumns {
+		page.ThreadsTable.Header[i] = col.title
+		page.ThreadsTable.ColWidths[i] = col.width
+		if i != 1 {
+			fixedWidth += col.width
+		}
+	}
+	page.ThreadsTable.CursorColor = ui.ColorCyan
+	page.ThreadsTable.ColColor[1] = ui.ColorGreen
+	page.ThreadsTable.ColResizer = func() {
+		// the name column takes the space left by the others
+		x := page.ThreadsTable.Inner.Dx() - fixedWidth
+		page.ThreadsTable.ColWidths[1] = ui.MaxInt(threadColumns[1].width, x)
+	}
+
+	// Initialize Table for Open Files Table
+	page.FDsTable.Title = unreadFDsTitle
+	page.FDsTable.BorderStyle.Fg = ui.ColorCyan
+	page.FDsTable.TitleStyle.Fg = ui.ColorClear
+	page.FDsTable.Header = []string{"FD", "Type", "Target", "State"}
+	page.FDsTable.ColWidths = []int{6, 11, 20, 12}
+	page.FDsTable.CursorColor = ui.ColorCyan
+	page.FDsTable.ColColor[2] = ui.ColorGreen
+	page.FDsTable.ColResizer = func() {
+		x := page.FDsTable.Inner.Dx() - 29
+		page.FDsTable.ColWidths[2] = ui.MaxInt(20, x)
+	}
+
 	// Initialize Bar Chart for CTX Switches Chart
 	page.CTXSwitchesChart.Data = []float64{0, 0}
 	page.CTXSwitchesChart.Labels = []string{"Volun", "Involun"}
@@ -123,20 +176,158 @@ func (page *perProcPage) init() {
 	page.MemStatsChart.LabelStyles = []ui.Style{ui.NewStyle(ui.ColorClear)}
 	page.MemStatsChart.NumStyles = []ui.Style{ui.NewStyle(ui.ColorBlack)}
 
+	// Initialize Line Graphs for the history of the process
+	initHistoryGraph(page.CPUGraph, " CPU % History ", ui.ColorGreen)
+	initHistoryGraph(page.RSSGraph, " RSS History ", ui.ColorMagenta)
+	initHistoryGraph(page.ThreadsGraph, " Threads History ", ui.ColorYellow)
+	initHistoryGraph(page.CTXSwitchesGraph, " Ctx switches/s History ", ui.ColorCyan)
+	initHistoryGraph(page.DiskIOGraph, " Disk I/O History ", ui.ColorGreen)
+	page.DiskIOGraph.LineColors["Read"] = ui.ColorGreen
+	page.DiskIOGraph.LineColors["Write"] = ui.ColorMagenta
+
+	// Initialize Tabs and Table for the details of the process
+	page.DetailsTabs.Title = " Details "
+	page.DetailsTabs.BorderStyle.Fg = ui.ColorCyan
+	page.DetailsTabs.TitleStyle.Fg = ui.ColorClear
+	page.DetailsTabs.ActiveTabStyle = ui.NewStyle(ui.ColorGreen, ui.ColorClear, ui.ModifierBold)
+	page.DetailsTabs.InactiveTabStyle = ui.NewStyle(ui.ColorClear)
+	page.DetailsTable.BorderStyle.Fg = ui.ColorCyan
+	page.DetailsTable.TitleStyle.Fg = ui.ColorClear
+	page.DetailsTable.CursorColor = ui.ColorCyan
+	page.DetailsTable.RowStyle = ui.NewStyle(ui.ColorClear)
+	page.DetailsTable.ColColor[0] = ui.ColorGreen
+	page.setDetailsTab(0)
+
 	// Initialize Grid layout
 	page.Grid.Set(
 		ui.NewCol(0.5,
-			ui.NewRow(0.125, page.CPUChart),
-			ui.NewRow(0.125, page.MemChart),
-			ui.NewRow(0.35, page.PIDTable),
-			ui.NewRow(0.4, page.ChildProcsTable),
+			ui.NewRow(0.1, page.CPUChart),
+			ui.NewRow(0.1, page.MemChart),
+			ui.NewRow(0.3, page.PIDTable),
+			ui.NewRow(0.15, page.ChildProcsTable),
+			ui.NewRow(0.35, page.ThreadsTable),
 		),
 		ui.NewCol(0.5,
-			ui.NewRow(0.6,
-				ui.NewCol(0.5, page.CTXSwitchesChart),
-				ui.NewCol(0.5, page.PageFaultsChart),
+			ui.NewRow(0.35,
+				ui.NewCol(0.5,
+					ui.NewRow(0.5, page.CPUGraph),
+					ui.NewRow(0.5, page.RSSGraph),
+				),
+				ui.NewCol(0.5,
+					ui.NewRow(0.5, page.ThreadsGraph),
+					ui.NewRow(0.5, page.CTXSwitchesGraph),
+				),
+			),
+			ui.NewRow(0.15, page.DiskIOGraph),
+			ui.NewRow(0.25, page.FDsTable),
+			ui.NewRow(0.25,
+				ui.NewCol(0.25, page.CTXSwitchesChart),
+				ui.NewCol(0.25, page.PageFaultsChart),
+				ui.NewCol(0.5, page.MemStatsChart),
 			),
-			ui.NewRow(0.4, page.MemStatsChart),
+		),
+	)
+
+	w, h := ui.TerminalDimensions()
+	page.Grid.SetRect(0, 0, w, h)
+}
+
+// setDetailsTab sets the header and column widths of the details table to
+// show a tab of the details of the process.
+func (page *perProcPage) setDetailsTab(idx int) {
+	tab := detailsTabs[idx]
+	page.DetailsTabs.ActiveTabIndex = idx
+	page.DetailsTable.Header = tab.header
+	page.DetailsTable.ColWidths = append([]int{}, tab.widths...)
+	page.DetailsTable.Rows = [][]string{}
+	page.DetailsTable.SelectedRow, page.DetailsTable.TopRow = 0, 0
+	page.DetailsTable.ColResizer = func() {
+		// the last column takes the space left by the others
+		last := len(tab.widths) - 1
+		x := page.DetailsTable.Inner.Dx()
+		for _, width := range tab.widths[:last] {
+			x -= width
+		}
+		page.DetailsTable.ColWidths[last] = ui.MaxInt(tab.widths[last], x)
+	}
+}
+
+// initHistoryGraph initializes a Line Graph drawing a single series
+func initHistoryGraph(graph *viz.LineGraph, title string, color ui.Color) {
+	graph.Title = title
+	graph.TitleStyle = ui.NewStyle(ui.ColorClear)
+	graph.BorderStyle.Fg = ui.ColorCyan
+	graph.DefaultLineColor = color
+	graph.HorizontalScale = 2
+}
+
+// comparePage holds the ui elements rendered by the command
+// grofer proc -p PID,PID, and by grofer proc while processes are compared
+type comparePage struct {
+	Grid             *ui.Grid
+	Table            *widgets.Table
+	CPUGraph         *viz.LineGraph
+	RSSGraph         *viz.LineGraph
+	ThreadsGraph     *viz.LineGraph
+	CTXSwitchesGraph *viz.LineGraph
+	PageFaultsGraph  *viz.LineGraph
+}
+
+// newComparePage initializes a new page from the comparePage struct and returns it
+func newComparePage() *comparePage {
+	page := &comparePage{
+		Grid:             ui.NewGrid(),
+		Table:            widgets.NewTable(),
+		CPUGraph:         viz.NewLineGraph(),
+		RSSGraph:         viz.NewLineGraph(),
+		ThreadsGraph:     viz.NewLineGraph(),
+		CTXSwitchesGraph: viz.NewLineGraph(),
+		PageFaultsGraph:  viz.NewLineGraph(),
+	}
+	page.init()
+	return page
+}
+
+// init initializes and sets the ui and grid for the comparison of processes
+func (page *comparePage) init() {
+	// Initialize Table for the metrics of the processes side by side
+	page.Table.Title = " Comparison "
+	page.Table.TextStyle = ui.NewStyle(ui.ColorClear)
+	page.Table.RowSeparator = false
+	page.Table.BorderStyle.Fg = ui.ColorCyan
+	page.Table.TitleStyle.Fg = ui.ColorClear
+	page.Table.ColumnResizer = func() {
+		// the processes share the space left by the metric names
+		count := len(page.Table.Rows[0]) - 1
+		if count < 1 {
+			return
+		}
+		x := page.Table.Inner.Dx() - compareLabelWidth
+		page.Table.ColumnWidths = []int{compareLabelWidth}
+		for i := 0; i < count; i++ {
+			page.Table.ColumnWidths = append(page.Table.ColumnWidths, x/count)
+		}
+	}
+	page.Table.Rows = [][]string{{""}}
+
+	// Initialize Line Graphs drawing a series for every process
+	initHistoryGraph(page.CPUGraph, " CPU % ", ui.ColorClear)
+	initHistoryGraph(page.RSSGraph, " RSS ", ui.ColorClear)
+	initHistoryGraph(page.ThreadsGraph, " Threads ", ui.ColorClear)
+	initHistoryGraph(page.CTXSwitchesGraph, " Ctx switches/s ", ui.ColorClear)
+	initHistoryGraph(page.PageFaultsGraph, " Page faults/s ", ui.ColorClear)
+
+	// Initialize Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.4, page.Table),
+		ui.NewRow(0.3,
+			ui.NewCol(0.5, page.CPUGraph),
+			ui.NewCol(0.5, page.RSSGraph),
+		),
+		ui.NewRow(0.3,
+			ui.NewCol(1.0/3, page.ThreadsGraph),
+			ui.NewCol(1.0/3, page.CTXSwitchesGraph),
+			ui.NewCol(1.0/3, page.PageFaultsGraph),
 		),
 	)
 
@@ -146,55 +337,131 @@ func (page *perProcPage) init() {
 
 // allProcPage struct holds the ui elements rendered by the grofer proc command
 type allProcPage struct {
-	Grid      *ui.Grid
-	ProcTable *viz.Table
+	Grid        *ui.Grid
+	ProcTable   *viz.Table
+	EventsTable *viz.Table
+	FilterBox   *widgets.Paragraph
+	PromptBox   *widgets.Paragraph
 }
 
-// newAllProcPage initializes a new page from the allProcPage struct and returns it
-func newAllProcPage() *allProcPage {
+// newAllProcPage initializes a new page from the allProcPage struct with the
+// given columns and returns it
+func newAllProcPage(cols []column) *allProcPage {
 	page := &allProcPage{
-		Grid:      ui.NewGrid(),
-		ProcTable: viz.NewTable(),
+		Grid:        ui.NewGrid(),
+		ProcTable:   viz.NewTable(),
+		EventsTable: viz.NewTable(),
+		FilterBox:   widgets.NewParagraph(),
+		PromptBox:   widgets.NewParagraph(),
 	}
-	page.init()
+	page.init(cols)
 	return page
 }
 
 // init initializes and sets the ui and grid for grofer proc
-func (page *allProcPage) init() {
-	page.ProcTable.Header = []string{
-		"PID",
-		"Command",
-		"CPU",
-		"Memory",
-		"Status",
-		"Foreground",
-		"Creation Time",
-		"Thread Count",
-	}
-	page.ProcTable.ColWidths = []int{10, 40, 10, 10, 8, 12, 25, 15}
-	page.ProcTable.ColResizer = func() {
-		x := page.ProcTable.Inner.Dx() - (10 + 10 + 10 + 8 + 12 + 25 + 15)
-		page.ProcTable.ColWidths = []int{
-			10,
-			ui.MaxInt(40, x),
-			10,
-			10,
-			8,
-			12,
-			25,
-			15,
-		}
-	}
+func (page *allProcPage) init(cols []column) {
+	page.setColumns(cols)
 	page.ProcTable.ShowCursor = true
 	page.ProcTable.RowStyle = ui.NewStyle(ui.ColorClear)
-	page.ProcTable.ColColor[1] = ui.ColorGreen
 	page.ProcTable.DefaultBorderColor = ui.ColorCyan
 	page.ProcTable.ActiveBorderColor = ui.ColorCyan
-	page.Grid.Set(
-		ui.NewRow(1.0, page.ProcTable),
-	)
+
+	// Initialize Table for the log of started and exited processes
+	page.EventsTable.Title = getEventsTitle(nil)
+	page.EventsTable.Header = eventsHeader
+	page.EventsTable.ColWidths = append([]int{}, eventsColWidths...)
+	page.EventsTable.ColColor[4] = ui.ColorGreen
+	page.EventsTable.CursorColor = ui.ColorCyan
+	page.EventsTable.RowStyle = ui.NewStyle(ui.ColorClear)
+	page.EventsTable.DefaultBorderColor = ui.ColorCyan
+	page.EventsTable.ActiveBorderColor = ui.ColorCyan
+	page.EventsTable.ColResizer = func() {
+		x := page.EventsTable.Inner.Dx()
+		for i, width := range eventsColWidths {
+			if i != 4 {
+				x -= width
+			}
+		}
+		page.EventsTable.ColWidths[4] = ui.MaxInt(eventsColWidths[4], x)
+	}
+
+	// Initialize Paragraph for the filter prompt
+	page.FilterBox.Title = " Filter "
+	page.FilterBox.BorderStyle.Fg = ui.ColorCyan
+	page.FilterBox.TitleStyle.Fg = ui.ColorClear
+
+	// Initialize Paragraph for the prompt of process actions
+	page.PromptBox.BorderStyle.Fg = ui.ColorCyan
+	page.PromptBox.TitleStyle.Fg = ui.ColorClear
+
+	page.showEvents(false)
+}
+
+// showEvents shows or hides the process events table below the process
+// table.
+func (page *allProcPage) showEvents(show bool) {
+	page.Grid = ui.NewGrid()
+	if show {
+		page.Grid.Set(
+			ui.NewRow(0.65, page.ProcTable),
+			ui.NewRow(0.35, page.EventsTable),
+		)
+	} else {
+		page.Grid.Set(
+			ui.NewRow(1.0, page.ProcTable),
+		)
+	}
 
 	w, h := ui.TerminalDimensions()
 	page.Grid.SetRect(0, 0, w, h)
 }
+
+// setColumns sets the header and column widths of the table to show the
+// given process columns.
+func (page *allProcPage) setColumns(cols []column) {
+	page.ProcTable.Header = make([]string, len(cols))
+	page.ProcTable.ColWidths = make([]int, len(cols))
+	page.ProcTable.ColColor = make(map[int]ui.Color)
+	fixedWidth, flexCols := 0, 0
+	for i, col := range cols {
+		page.ProcTable.Header[i] = col.title
+		page.ProcTable.ColWidths[i] = col.width
+		if col.flex {
+			flexCols++
+		} else {
+			fixedWidth += col.width
+		}
+		if col.indent {
+			page.ProcTable.ColColor[i] = ui.ColorGreen
+		}
+	}
+	page.ProcTable.ColResizer = func() {
+		// flexible columns share the space left by the others.
+		x := page.ProcTable.Inner.Dx() - fixedWidth
+		for i, col := range cols {
+			if col.flex {
+				page.ProcTable.ColWidths[i] = ui.MaxInt(col.width, x/flexCols)
+			}
+		}
+	}
+}
+
+// setGroupColumns sets the header and column widths of the table to show
+// groups of processes.
+func (page *allProcPage) setGroupColumns() {
+	page.ProcTable.Header = make([]string, len(groupColumns))
+	page.ProcTable.ColWidths = make([]int, len(groupColumns))
+	page.ProcTable.ColColor = map[int]ui.Color{0: ui.ColorGreen}
+	fixedWidth := 0
+	for i, col := range groupColumns {
+		page.ProcTable.Header[i] = col.title
+		page.ProcTable.ColWidths[i] = col.width
+		if i > 0 {
+			fixedWidth += col.width
+		}
+	}
+	page.ProcTable.ColResizer = func() {
+		x := page.ProcTable.Inner.Dx() - fixedWidth
+		page.ProcTable.ColWidths[0] = ui.MaxInt(groupColumns[0].width, x)
+	}
+}
@@ -18,6 +18,7 @@ package process
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strconv"
 	"sync"
@@ -31,6 +32,17 @@ import (
 	viz "github.com/pesos/grofer/pkg/utils/visualization"
 )
 
+// statusMap holds the name of every process status
+var statusMap = map[string]string{
+	"R": "Running",
+	"S": "Sleep",
+	"Z": "Zombie",
+	"T": "Stop",
+	"I": "Idle",
+	"W": "Wait",
+	"L": "Lock",
+}
+
 func getChildProcs(proc *process.Process) [][]string {
 	childProcs := [][]string{}
 	for _, proc := range proc.Children {
@@ -45,10 +57,27 @@ func getChildProcs(proc *process.Process) [][]string {
 	return childProcs
 }
 
-// ProcVisuals renders graphs and charts for per-process stats.
+// getPIDTitle returns the title of the process table, telling whether the
+// process exited and how many times another process was followed in its
+// place.
+func getPIDTitle(proc *process.Process, restarts int) string {
+	title := " PID: " + strconv.Itoa(int(proc.Proc.Pid))
+	if !proc.IsRunning || proc.Status == "Z" {
+		title += " (exited)"
+	} else if restarts == 1 {
+		title += " (restarted once)"
+	} else if restarts > 1 {
+		title += fmt.Sprintf(" (restarted %d times)", restarts)
+	}
+	return title + " "
+}
+
+// ProcVisuals renders graphs and charts for per-process stats. It turns on
+// the readings of the panels it shows in readings.
 func ProcVisuals(ctx context.Context,
 	dataChannel chan *process.Process,
-	refreshRate uint64) error {
+	refreshRate uint64,
+	readings *process.Readings) error {
 
 	defer ui.Close()
 
@@ -62,17 +91,60 @@ func ProcVisuals(ctx context.Context,
 	// Create new page and select default table
 	page := newPerProcPage()
 	utilitySelected := core.None
-	var scrollableWidget viz.ScrollableWidget = page.ChildProcsTable
+	// the threads table is always on screen, while the open files and the
+	// socket tables describing them are only read while it is focused.
+	readings.Set(process.ReadThreads, true)
+	// table that is scrolled when no utility is displayed
+	focusedTable := page.ChildProcsTable
+	var scrollableWidget viz.ScrollableWidget = focusedTable
 	scrollableWidget.EnableCursor()
 
-	var statusMap = map[string]string{
-		"R": "Running",
-		"S": "Sleep",
-		"Z": "Zombie",
-		"T": "Stop",
-		"I": "Idle",
-		"W": "Wait",
-		"L": "Lock",
+	// sorting of the threads table and the latest threads received
+	sortIdx := -1
+	sortAsc := false
+	var threads []process.Thread
+	setThreadRows := func() {
+		page.ThreadsTable.Rows = getThreadRows(threads, sortIdx, sortAsc)
+	}
+	setThreadSort := func(idx int, asc bool) {
+		page.ThreadsTable.Header = make([]string, len(threadColumns))
+		for i, col := range threadColumns {
+			page.ThreadsTable.Header[i] = col.title
+		}
+		sortIdx, sortAsc = idx, asc
+		if sortIdx != -1 {
+			arrow := viz.DownArrow
+			if sortAsc {
+				arrow = viz.UpArrow
+			}
+			page.ThreadsTable.Header[sortIdx] += " " + arrow
+		}
+		setThreadRows()
+	}
+
+	// the latest update of the process, shown in the selected details tab
+	var latest *process.Process
+	setDetailsRows := func() {
+		if latest == nil {
+			return
+		}
+		tab := detailsTabs[page.DetailsTabs.ActiveTabIndex]
+		page.DetailsTable.Rows, page.DetailsTable.Title = getDetailsRows(tab, latest)
+	}
+	selectDetailsTab := func(idx int) {
+		page.setDetailsTab(idx)
+		setDetailsRows()
+	}
+
+	// history of the process over the session, and the number of times
+	// another process was followed in its place
+	var history procHistory
+	var pid int32
+	restarts := 0
+	setHistory := func(graph *viz.LineGraph, s *series, value string) {
+		graph.Data["Now"] = s.data
+		graph.Labels["Now"] = value
+		graph.Markers = s.markersAgo()
 	}
 
 	// variables to pause UI render
@@ -87,13 +159,13 @@ func ProcVisuals(ctx context.Context,
 		w, h := ui.TerminalDimensions()
 
 		// Adjust Memory Stats Bar graph values
-		page.MemStatsChart.BarGap = ((w / 2) - (4 * page.MemStatsChart.BarWidth)) / 4
+		page.MemStatsChart.BarGap = ui.MaxInt(0, ((w/4)-(4*page.MemStatsChart.BarWidth))/4)
 
 		// Adjust Page Faults Bar graph values
-		page.PageFaultsChart.BarGap = ((w / 4) - (2 * page.PageFaultsChart.BarWidth)) / 2
+		page.PageFaultsChart.BarGap = ui.MaxInt(0, ((w/8)-(2*page.PageFaultsChart.BarWidth))/2)
 
 		// Adjust Context Switches Bar graph values
-		page.CTXSwitchesChart.BarGap = ((w / 4) - (2 * page.CTXSwitchesChart.BarWidth)) / 2
+		page.CTXSwitchesChart.BarGap = ui.MaxInt(0, ((w/8)-(2*page.CTXSwitchesChart.BarWidth))/2)
 
 		// Adjust Grid dimensions
 		page.Grid.SetRect(0, 0, w, h)
@@ -105,6 +177,11 @@ func ProcVisuals(ctx context.Context,
 			help.Resize(w, h)
 			ui.Render(help)
 
+		case core.Details:
+			page.DetailsTabs.SetRect(0, 0, w, 3)
+			page.DetailsTable.SetRect(0, 3, w, h)
+			ui.Render(page.DetailsTabs, page.DetailsTable)
+
 		default:
 			ui.Render(page.Grid)
 		}
@@ -122,6 +199,21 @@ func ProcVisuals(ctx context.Context,
 			return ctx.Err()
 
 		case e := <-uiEvents:
+			// while the details are shown keys switch between their tabs
+			if utilitySelected == core.Details {
+				switch e.ID {
+				case "h", "<Left>":
+					selectDetailsTab(ui.MaxInt(0, page.DetailsTabs.ActiveTabIndex-1))
+				case "l", "<Right>":
+					selectDetailsTab(ui.MinInt(len(detailsTabs)-1, page.DetailsTabs.ActiveTabIndex+1))
+				case "1", "2", "3", "4", "5", "6":
+					idx, _ := strconv.Atoi(e.ID)
+					if idx <= len(detailsTabs) {
+						selectDetailsTab(idx - 1)
+					}
+				}
+			}
+
 			switch e.ID {
 			case "q", "<C-c>": //q or Ctrl-C to quit
 				return core.ErrCanceledByUser
@@ -140,12 +232,60 @@ func ProcVisuals(ctx context.Context,
 				pause()
 
 			case "<Escape>":
+				readings.Set(process.ReadDetails, false)
 				utilitySelected = core.None
 				scrollableWidget.DisableCursor()
-				scrollableWidget = page.ChildProcsTable
+				scrollableWidget = focusedTable
 				scrollableWidget.EnableCursor()
 				updateUI()
 
+			// show the details of the process
+			case "d":
+				if utilitySelected == core.None {
+					scrollableWidget.DisableCursor()
+					scrollableWidget = page.DetailsTable
+					scrollableWidget.EnableCursor()
+					utilitySelected = core.Details
+					readings.Set(process.ReadDetails, true)
+					setDetailsRows()
+					updateUI()
+				}
+
+			// switch between the child processes, threads and open files tables
+			case "<Tab>":
+				if utilitySelected == core.None {
+					scrollableWidget.DisableCursor()
+					switch focusedTable {
+					case page.ChildProcsTable:
+						focusedTable = page.ThreadsTable
+					case page.ThreadsTable:
+						focusedTable = page.FDsTable
+					default:
+						focusedTable = page.ChildProcsTable
+					}
+					scrollableWidget = focusedTable
+					scrollableWidget.EnableCursor()
+					readFDs := focusedTable == page.FDsTable
+					readings.Set(process.ReadFDs, readFDs)
+					if !readFDs {
+						page.FDsTable.Rows = nil
+						page.FDsTable.Title = unreadFDsTitle
+					}
+				}
+
+			// sort the threads table
+			case "0", "1", "2", "3", "4", "5", "6", "7":
+				if utilitySelected == core.None {
+					idx, _ := strconv.Atoi(e.ID)
+					setThreadSort(idx-1, true)
+				}
+
+			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>":
+				if utilitySelected == core.None {
+					idx, _ := strconv.Atoi(e.ID[2:3])
+					setThreadSort(idx-1, false)
+				}
+
 			// handle table navigations
 			case "j", "<Down>":
 				scrollableWidget.ScrollDown()
@@ -185,6 +325,14 @@ func ProcVisuals(ctx context.Context,
 			}
 
 		case data := <-dataChannel:
+			if pid != 0 && data.Proc.Pid != pid {
+				history.restart()
+				restarts++
+			}
+			pid = data.Proc.Pid
+			// the history is recorded while paused, only the page is frozen.
+			history.add(data, time.Now())
+
 			if runProc {
 				// update ctx switches
 				switches, units := utils.RoundValues(float64(data.NumCtxSwitches.Voluntary), float64(data.NumCtxSwitches.Involuntary), false)
@@ -212,7 +360,7 @@ func ProcVisuals(ctx context.Context,
 					{"[Child process count](fg:green)", strconv.Itoa(len(data.Children))},
 					{"[Last Update](fg:green)", time.Now().Format("15:04:05")},
 				}
-				page.PIDTable.Title = " PID: " + strconv.Itoa(int(data.Proc.Pid)) + " "
+				page.PIDTable.Title = getPIDTitle(data, restarts)
 
 				//update memory stats
 				memData := []float64{utils.GetInMB(data.MemoryInfo.RSS, 1),
@@ -228,13 +376,41 @@ func ProcVisuals(ctx context.Context,
 				page.PageFaultsChart.Data = faults
 				page.PageFaultsChart.Title = " Page Faults" + units
 				page.ChildProcsTable.Rows = getChildProcs(data)
+				threads = data.Threads
+				setThreadRows()
+				if focusedTable == page.FDsTable {
+					page.FDsTable.Rows = getFDRows(data.FDs)
+					page.FDsTable.Title = getFDTitle(len(data.FDs), data.FDLimit)
+				}
+				latest = data
+				setDetailsRows()
+
+				// update history graphs
+				setHistory(page.CPUGraph, &history.cpu,
+					fmt.Sprintf("%.2f%% (peak %.2f%%)", history.cpu.last(), history.cpu.peak))
+				setHistory(page.RSSGraph, &history.rss,
+					fmt.Sprintf("%s (peak %s)", formatBytes(uint64(history.rss.last())), formatBytes(uint64(history.rss.peak))))
+				setHistory(page.ThreadsGraph, &history.threads,
+					fmt.Sprintf("%.0f (peak %.0f)", history.threads.last(), history.threads.peak))
+				setHistory(page.CTXSwitchesGraph, &history.ctxSwitches,
+					fmt.Sprintf("%.1f (peak %.1f)", history.ctxSwitches.last(), history.ctxSwitches.peak))
+				page.DiskIOGraph.Data["Read"] = history.diskRead.data
+				page.DiskIOGraph.Labels["Read"] = fmt.Sprintf("%s (peak %s)",
+					formatByteRate(history.diskRead.last()), formatByteRate(history.diskRead.peak))
+				page.DiskIOGraph.Data["Write"] = history.diskWrite.data
+				page.DiskIOGraph.Labels["Write"] = fmt.Sprintf("%s (peak %s)",
+					formatByteRate(history.diskWrite.last()), formatByteRate(history.diskWrite.peak))
+				page.DiskIOGraph.Markers = history.diskRead.markersAgo()
 
 				on.Do(updateUI)
 			}
 
 		case <-tick:
-			if utilitySelected == core.None {
+			switch utilitySelected {
+			case core.None:
 				ui.Render(page.Grid)
+			case core.Details:
+				ui.Render(page.DetailsTabs, page.DetailsTable)
 			}
 		}
 	}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+// threadColumn describes a column of the threads table.
+type threadColumn struct {
+	title string
+	width int
+	value func(t process.Thread) string
+	less  func(a, b process.Thread) bool
+}
+
+// threadColumns are the columns of the threads table, the name column
+// takes the space left by the others.
+var threadColumns = []threadColumn{
+	{
+		title: "TID",
+		width: 8,
+		value: func(t process.Thread) string { return fmt.Sprintf("%d", t.TID) },
+		less:  func(a, b process.Thread) bool { return a.TID < b.TID },
+	},
+	{
+		title: "Name",
+		width: 16,
+		value: func(t process.Thread) string { return t.Name },
+		less:  func(a, b process.Thread) bool { return a.Name < b.Name },
+	},
+	{
+		title: "State",
+		width: 6,
+		value: func(t process.Thread) string { return t.State },
+		less:  func(a, b process.Thread) bool { return a.State < b.State },
+	},
+	{
+		title: "CPU",
+		width: 9,
+		value: func(t process.Thread) string { return fmt.Sprintf("%.2f%%", t.CPUPercent) },
+		less:  func(a, b process.Thread) bool { return a.CPUPercent < b.CPUPercent },
+	},
+	{
+		title: "Last CPU",
+		width: 9,
+		value: func(t process.Thread) string {
+			if t.Processor < 0 {
+				return "NA"
+			}
+			return fmt.Sprintf("%d", t.Processor)
+		},
+		less: func(a, b process.Thread) bool { return a.Processor < b.Processor },
+	},
+	{
+		title: "Vol Ctx",
+		width: 10,
+		value: func(t process.Thread) string { return fmt.Sprintf("%d", t.VoluntarySwitches) },
+		less:  func(a, b process.Thread) bool { return a.VoluntarySwitches < b.VoluntarySwitches },
+	},
+	{
+		title: "Invol Ctx",
+		width: 10,
+		value: func(t process.Thread) string { return fmt.Sprintf("%d", t.InvoluntarySwitches) },
+		less:  func(a, b process.Thread) bool { return a.InvoluntarySwitches < b.InvoluntarySwitches },
+	},
+}
+
+// getThreadRows returns the rows of the threads table, ordered by the sort
+// column if sortIdx is not -1.
+func getThreadRows(threads []process.Thread, sortIdx int, sortAsc bool) [][]string {
+	if sortIdx != -1 {
+		threads = append([]process.Thread{}, threads...)
+		less := threadColumns[sortIdx].less
+		sort.SliceStable(threads, func(i, j int) bool {
+			if sortAsc {
+				return less(threads[i], threads[j])
+			}
+			return less(threads[j], threads[i])
+		})
+	}
+
+	rows := make([][]string, 0, len(threads))
+	for _, t := range threads {
+		row := make([]string, len(threadColumns))
+		for i, col := range threadColumns {
+			row[i] = col.value(t)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestGetThreadRows(t *testing.T) {
+	threads := []process.Thread{
+		{TID: 10, Name: "main", CPUPercent: 1.5, Processor: 2},
+		{TID: 11, Name: "worker", CPUPercent: 99, Processor: -1},
+		{TID: 9, Name: "gc", CPUPercent: 20, Processor: 0},
+	}
+
+	rows := getThreadRows(threads, -1, false)
+	utils.Equals(t, []string{"11", "worker", "", "99.00%", "NA", "0", "0"}, rows[1])
+
+	// CPU sorts numerically, the busiest thread first.
+	rows = getThreadRows(threads, 3, false)
+	utils.Equals(t, "11", rows[0][0])
+	utils.Equals(t, "9", rows[1][0])
+	utils.Equals(t, "10", rows[2][0])
+
+	// the threads received are left in order.
+	utils.Equals(t, int32(10), threads[0].TID)
+}
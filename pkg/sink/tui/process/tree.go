@@ -0,0 +1,130 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"strings"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+)
+
+const (
+	treeIndent    = "  "
+	treeExpanded  = "▾ "
+	treeCollapsed = "▸ "
+	treeLeaf      = "  "
+)
+
+// procTree arranges a snapshot of processes under their parents and keeps
+// the CPU and memory usage of every subtree.
+type procTree struct {
+	// nodes holds the processes with the CPU and memory usage of their
+	// subtree in place of their own.
+	nodes    map[int32]process.Snapshot
+	children map[int32][]int32
+	roots    []int32
+}
+
+// newProcTree builds the tree of the given processes. Processes whose
+// parent is not part of the snapshot are roots.
+func newProcTree(procs []process.Snapshot) *procTree {
+	tree := &procTree{
+		nodes:    make(map[int32]process.Snapshot, len(procs)),
+		children: make(map[int32][]int32),
+	}
+
+	byPID := make(map[int32]process.Snapshot, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+	for _, p := range procs {
+		if _, ok := byPID[p.PPID]; ok && p.PPID != p.PID {
+			tree.children[p.PPID] = append(tree.children[p.PPID], p.PID)
+		} else {
+			tree.roots = append(tree.roots, p.PID)
+		}
+	}
+
+	for _, pid := range tree.roots {
+		tree.total(pid, byPID)
+	}
+	return tree
+}
+
+// total computes the usage of the subtree rooted at pid.
+func (tree *procTree) total(pid int32, byPID map[int32]process.Snapshot) {
+	node := byPID[pid]
+	for _, child := range tree.children[pid] {
+		tree.total(child, byPID)
+		node.CPUPercent += tree.nodes[child].CPUPercent
+		node.MemoryPercent += tree.nodes[child].MemoryPercent
+	}
+	tree.nodes[pid] = node
+}
+
+// getRows returns the rows of the table with every process indented under
+// its parent and the children of collapsed processes hidden, along with the
+// PID of every row. Siblings are ordered by the sort column if sortIdx is
+// not -1.
+func (tree *procTree) getRows(cols []column, collapsed map[int32]bool, sortIdx int, sortAsc bool) ([][]string, []int32) {
+	rows := make([][]string, 0, len(tree.nodes))
+	pids := make([]int32, 0, len(tree.nodes))
+
+	indentIdx := -1
+	for i, col := range cols {
+		if col.indent {
+			indentIdx = i
+			break
+		}
+	}
+
+	var walk func(siblingPIDs []int32, depth int)
+	walk = func(siblingPIDs []int32, depth int) {
+		siblings := make([]process.Snapshot, 0, len(siblingPIDs))
+		for _, pid := range siblingPIDs {
+			siblings = append(siblings, tree.nodes[pid])
+		}
+		if sortIdx != -1 {
+			sortProcs(siblings, cols[sortIdx], sortAsc)
+		}
+
+		for _, sibling := range siblings {
+			children := tree.children[sibling.PID]
+
+			row := getRow(sibling, cols)
+			if indentIdx != -1 {
+				marker := treeLeaf
+				if len(children) > 0 {
+					marker = treeExpanded
+					if collapsed[sibling.PID] {
+						marker = treeCollapsed
+					}
+				}
+				row[indentIdx] = strings.Repeat(treeIndent, depth) + marker + row[indentIdx]
+			}
+			rows = append(rows, row)
+			pids = append(pids, sibling.PID)
+
+			if !collapsed[sibling.PID] {
+				walk(children, depth+1)
+			}
+		}
+	}
+	walk(tree.roots, 0)
+
+	return rows, pids
+}
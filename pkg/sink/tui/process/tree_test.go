@@ -0,0 +1,60 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"testing"
+
+	"github.com/pesos/grofer/pkg/metrics/process"
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestProcTree(t *testing.T) {
+	procs := []process.Snapshot{
+		{PID: 1, PPID: 0, Name: "init", CPUPercent: 1},
+		{PID: 10, PPID: 1, Name: "make", CPUPercent: 2, MemoryPercent: 1},
+		{PID: 11, PPID: 10, Name: "cc", CPUPercent: 30, MemoryPercent: 2},
+		{PID: 12, PPID: 10, Name: "cc", CPUPercent: 40, MemoryPercent: 3},
+		{PID: 20, PPID: 1, Name: "sshd"},
+		// the parent of an orphan that was not sampled.
+		{PID: 30, PPID: 99, Name: "orphan"},
+	}
+	tree := newProcTree(procs)
+	cols, err := getColumns(nil)
+	utils.Raises(t, err)
+
+	rows, pids := tree.getRows(cols, map[int32]bool{}, -1, false)
+	commands := []string{}
+	for _, row := range rows {
+		commands = append(commands, row[1])
+	}
+	utils.Equals(t, []string{"▾ init", "  ▾ make", "      cc", "      cc", "    sshd", "  orphan"}, commands)
+	utils.Equals(t, []int32{1, 10, 11, 12, 20, 30}, pids)
+	utils.Equals(t, "73.00%", rows[0][2])
+	utils.Equals(t, "72.00%", rows[1][2])
+	utils.Equals(t, "6.00%", rows[1][3])
+
+	// collapsing make hides its children but keeps its totals.
+	rows, _ = tree.getRows(cols, map[int32]bool{10: true}, -1, false)
+	utils.Equals(t, 4, len(rows))
+	utils.Equals(t, "  ▸ make", rows[1][1])
+	utils.Equals(t, "72.00%", rows[1][2])
+
+	// siblings are sorted by the total CPU of their subtree.
+	_, pids = tree.getRows(cols, map[int32]bool{}, 2, true)
+	utils.Equals(t, []int32{30, 1, 20, 10, 11, 12}, pids)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy decides what happens to a sample published to a subscriber
+// whose buffer is full.
+type DropPolicy int
+
+const (
+	// Block waits until the subscriber has room for the sample. A slow
+	// subscriber with this policy stalls the publisher.
+	Block DropPolicy = iota
+	// DropNewest discards the sample being published.
+	DropNewest
+	// DropOldest discards the oldest buffered sample to make room for the
+	// sample being published, so the subscriber always sees the latest one.
+	// On a Broadcaster with a key, the oldest buffered sample with the same
+	// key as the sample being published is discarded if there is one.
+	DropOldest
+)
+
+// Subscription is a channel registered with a Broadcaster.
+type Subscription struct {
+	ch      reflect.Value
+	key     func(sample interface{}) string
+	dropped uint64
+	policy  DropPolicy
+}
+
+// Dropped returns the number of samples that were dropped for this subscriber.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// send delivers a sample to the subscriber according to its drop policy.
+func (s *Subscription) send(ctx context.Context, v reflect.Value) error {
+	switch s.policy {
+	case DropNewest:
+		if !s.ch.TrySend(v) {
+			atomic.AddUint64(&s.dropped, 1)
+		}
+
+	case DropOldest:
+		if s.key != nil {
+			if !s.ch.TrySend(v) {
+				s.replace(v)
+			}
+			break
+		}
+		for !s.ch.TrySend(v) {
+			if _, ok := s.ch.TryRecv(); ok {
+				atomic.AddUint64(&s.dropped, 1)
+			}
+		}
+
+	default:
+		chosen, _, _ := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectSend, Chan: s.ch, Send: v},
+		})
+		if chosen == 0 {
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// replace makes room for a sample in the full buffer of the subscriber by
+// discarding the oldest buffered sample with the same key, or the oldest
+// sample if none has that key, keeping the order of the others.
+func (s *Subscription) replace(v reflect.Value) {
+	key := s.key(v.Interface())
+	buffered := []reflect.Value{}
+	for {
+		sample, ok := s.ch.TryRecv()
+		if !ok {
+			break
+		}
+		buffered = append(buffered, sample)
+	}
+
+	if len(buffered) > 0 {
+		drop := 0
+		for i, sample := range buffered {
+			if s.key(sample.Interface()) == key {
+				drop = i
+				break
+			}
+		}
+		buffered = append(buffered[:drop], buffered[drop+1:]...)
+		atomic.AddUint64(&s.dropped, 1)
+	}
+
+	for _, sample := range append(buffered, v) {
+		if !s.ch.TrySend(sample) {
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
+
+// Broadcaster fans out every published sample to all of its subscribers,
+// allowing a single producer to feed several sinks. Each subscriber brings
+// its own typed channel, whose capacity is its buffer, and drop policy.
+type Broadcaster struct {
+	elemType    reflect.Type
+	key         func(sample interface{}) string
+	subscribers []*Subscription
+	mu          sync.RWMutex
+}
+
+// NewBroadcaster is a constructor for the Broadcaster type. sample is a
+// value of the type that will be published, for ex - a zero value.
+func NewBroadcaster(sample interface{}) *Broadcaster {
+	return &Broadcaster{
+		elemType: reflect.TypeOf(sample),
+	}
+}
+
+// WithKey sets the function returning the key of a published sample, for
+// ex - the kind of metrics it holds when samples only hold some of them.
+// Subscribers using DropOldest then only discard a buffered sample for a
+// newer one with the same key, and keep the latest sample of every key if
+// their buffer holds as many samples as there are keys. It must be set
+// before subscribing.
+func (b *Broadcaster) WithKey(key func(sample interface{}) string) *Broadcaster {
+	b.key = key
+	return b
+}
+
+// Subscribe registers ch, which must be a channel of the published type
+// that the Broadcaster can send on, and returns its Subscription. The
+// DropOldest policy needs a buffered, bidirectional channel.
+func (b *Broadcaster) Subscribe(ch interface{}, policy DropPolicy) (*Subscription, error) {
+	v := reflect.ValueOf(ch)
+	if v.Kind() != reflect.Chan || v.Type().ChanDir()&reflect.SendDir == 0 {
+		return nil, fmt.Errorf("cannot subscribe %T: not a channel that can be sent on", ch)
+	}
+	if v.Type().Elem() != b.elemType {
+		return nil, fmt.Errorf("cannot subscribe %T: expected a channel of %v", ch, b.elemType)
+	}
+	if policy == DropOldest && v.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil, fmt.Errorf("cannot subscribe %T: dropping the oldest sample needs a bidirectional channel", ch)
+	}
+	if policy == DropOldest && v.Cap() == 0 {
+		return nil, fmt.Errorf("cannot subscribe %T: dropping the oldest sample needs a buffered channel", ch)
+	}
+
+	sub := &Subscription{
+		ch:     v,
+		key:    b.key,
+		policy: policy,
+	}
+
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, sub)
+	b.mu.Unlock()
+
+	return sub, nil
+}
+
+// Publish sends a sample to every subscriber. It only blocks on
+// subscribers that use the Block policy, and returns early if the
+// context is cancelled while doing so.
+func (b *Broadcaster) Publish(ctx context.Context, sample interface{}) error {
+	v := reflect.ValueOf(sample)
+	if v.Type() != b.elemType {
+		return fmt.Errorf("cannot publish %T: expected %v", sample, b.elemType)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subscribers {
+		if err := sub.send(ctx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
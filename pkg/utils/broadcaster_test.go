@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 The PES Open Source Team pesos@pes.edu
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pesos/grofer/pkg/utils"
+)
+
+func TestBroadcasterDropPolicies(t *testing.T) {
+	ctx := context.Background()
+	b := utils.NewBroadcaster(0)
+
+	blocking := make(chan int, 3)
+	newest := make(chan int, 1)
+	oldest := make(chan int, 1)
+
+	_, err := b.Subscribe(blocking, utils.Block)
+	utils.Raises(t, err)
+	dropNewest, err := b.Subscribe(newest, utils.DropNewest)
+	utils.Raises(t, err)
+	dropOldest, err := b.Subscribe(oldest, utils.DropOldest)
+	utils.Raises(t, err)
+
+	for i := 1; i <= 3; i++ {
+		utils.Raises(t, b.Publish(ctx, i))
+	}
+
+	utils.Equals(t, []int{1, 2, 3}, []int{<-blocking, <-blocking, <-blocking})
+	utils.Equals(t, 1, <-newest)
+	utils.Equals(t, uint64(2), dropNewest.Dropped())
+	utils.Equals(t, 3, <-oldest)
+	utils.Equals(t, uint64(2), dropOldest.Dropped())
+}
+
+func TestBroadcasterDropOldestWithKey(t *testing.T) {
+	ctx := context.Background()
+	b := utils.NewBroadcaster("").WithKey(func(sample interface{}) string {
+		return sample.(string)[:3]
+	})
+
+	samples := make(chan string, 2)
+	sub, err := b.Subscribe(samples, utils.DropOldest)
+	utils.Raises(t, err)
+
+	// a full buffer only gives up a sample for a newer one of its kind.
+	for _, sample := range []string{"cpu1", "dsk1", "cpu2", "cpu3"} {
+		utils.Raises(t, b.Publish(ctx, sample))
+	}
+	utils.Equals(t, []string{"dsk1", "cpu3"}, []string{<-samples, <-samples})
+	utils.Equals(t, uint64(2), sub.Dropped())
+}
+
+func TestBroadcasterBlockHonoursContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := utils.NewBroadcaster("")
+
+	_, err := b.Subscribe(make(chan string), utils.Block)
+	utils.Raises(t, err)
+
+	cancel()
+	utils.Equals(t, context.Canceled, b.Publish(ctx, "sample"))
+}
+
+func TestBroadcasterSubscribeValidation(t *testing.T) {
+	b := utils.NewBroadcaster(0)
+
+	_, err := b.Subscribe(make(chan string), utils.Block)
+	utils.Assert(t, err != nil, "expected an error subscribing a channel of the wrong type")
+
+	_, err = b.Subscribe(0, utils.Block)
+	utils.Assert(t, err != nil, "expected an error subscribing a non-channel")
+
+	_, err = b.Subscribe((chan<- int)(make(chan int)), utils.DropOldest)
+	utils.Assert(t, err != nil, "expected an error subscribing a send-only channel with DropOldest")
+
+	_, err = b.Subscribe(make(chan int), utils.DropOldest)
+	utils.Assert(t, err != nil, "expected an error subscribing an unbuffered channel with DropOldest")
+}
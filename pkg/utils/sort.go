@@ -28,15 +28,6 @@ import (
 func SortData(data [][]string, sortIdx int, sortAsc bool, sortCase string) {
 
 	// Define less functions
-	intSort := func(i, j int) bool {
-		x, _ := strconv.Atoi(data[i][sortIdx])
-		y, _ := strconv.Atoi(data[j][sortIdx])
-		if sortAsc {
-			return x < y
-		}
-		return x > y
-	}
-
 	strSort := func(i, j int) bool {
 		if sortAsc {
 			return data[i][sortIdx] < data[j][sortIdx]
@@ -58,17 +49,6 @@ func SortData(data [][]string, sortIdx int, sortAsc bool, sortCase string) {
 	// Set function map
 	sortFuncs := make(map[int]func(i, j int) bool)
 	switch sortCase {
-	case "PROCS":
-		sortFuncs = map[int]func(i, j int) bool{
-			0: intSort,   // PID
-			1: strSort,   // Command
-			3: floatSort, // CPU %
-			2: floatSort, // Memory %
-			4: strSort,   // Status
-			5: strSort,   // Foreground
-			6: strSort,   // Creation Time
-			7: intSort,   // Thread Count
-		}
 	case "CONTAINER":
 		sortFuncs = map[int]func(i, j int) bool{
 			0: strSort,   // ID
@@ -37,6 +37,11 @@ type LineGraph struct {
 
 	LineColors       map[string]ui.Color
 	DefaultLineColor ui.Color
+
+	// Markers are drawn as vertical lines at the given number of data
+	// points before the latest one.
+	Markers     []int
+	MarkerColor ui.Color
 }
 
 // NewLineGraph creates and returns a lineGraph instance
@@ -50,6 +55,7 @@ func NewLineGraph() *LineGraph {
 		HorizontalScale: 5,
 		MaxVal:          0, // Leave as 0 if you want the graph to resize depending on the values
 		LineColors:      make(map[string]ui.Color),
+		MarkerColor:     ui.ColorYellow,
 	}
 }
 
@@ -85,6 +91,20 @@ func (l *LineGraph) Draw(buf *ui.Buffer) {
 
 	sort.Strings(seriesList)
 
+	// draw markers first so that the lines are drawn over them
+	for _, m := range l.Markers {
+		col := (((l.Inner.Dx() + 1) * 2) - 1 - m*l.HorizontalScale) / 2
+		if col < 1 || col > l.Inner.Dx() {
+			continue
+		}
+		for y := l.Inner.Min.Y; y < l.Inner.Max.Y; y++ {
+			buf.SetCell(
+				ui.NewCell('┊', ui.NewStyle(l.MarkerColor)),
+				image.Pt(l.Inner.Min.X+col-1, y),
+			)
+		}
+	}
+
 	// draw lines in reverse order so that the first color defined in the colorscheme is on top
 	for i := len(seriesList) - 1; i >= 0; i-- {
 		seriesName := seriesList[i]
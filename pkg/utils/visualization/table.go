@@ -51,6 +51,9 @@ type Table struct {
 	ShowCursor  bool
 	CursorColor ui.Color
 
+	MarkedRows map[int]bool // rows drawn in MarkColor, ex - rows selected for a bulk action
+	MarkColor  ui.Color
+
 	ShowLocation bool
 
 	UniqueCol    int    // the column used to uniquely identify each table row
@@ -78,6 +81,7 @@ func NewTable() *Table {
 		ColResizer:         func() {},
 		ColColor:           make(map[int]ui.Color),
 		CursorColor:        ui.ColorCyan,
+		MarkColor:          ui.ColorYellow,
 		DefaultBorderColor: ui.ColorCyan,
 		ActiveBorderColor:  ui.ColorWhite,
 	}
@@ -129,6 +133,9 @@ func (t *Table) Draw(buf *ui.Buffer) {
 		y := (rowNum + 2) - t.TopRow
 		// prints cursor
 		style := t.RowStyle
+		if t.MarkedRows[rowNum] {
+			style.Fg = t.MarkColor
+		}
 		if t.IsHelp {
 			if len(t.Rows[rowNum][0]) > 0 && string(t.Rows[rowNum][0][0]) != " " {
 				style = t.HeaderStyle
@@ -162,7 +169,7 @@ func (t *Table) Draw(buf *ui.Buffer) {
 			if val, ok := t.ColColor[i]; ok {
 				if rowNum == t.SelectedRow && t.ShowCursor {
 					style.Fg = t.CursorColor
-				} else {
+				} else if !t.MarkedRows[rowNum] {
 					style.Fg = val
 				}
 			}